@@ -0,0 +1,94 @@
+package file
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+)
+
+const equalChunkSize = 32 * 1024
+
+// FilesEqual reports whether the contents of the two files are the same
+// byte by byte.
+//
+// The sizes are compared at first, then the contents are compared chunk
+// by chunk, which returns as soon as the first difference is found.
+//
+// Return false and nil if the files are different, and the error is returned
+// only for the IO problem, such as the file does not exist.
+func FilesEqual(a, b string) (bool, error) {
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	if ia, err := fa.Stat(); err != nil {
+		return false, err
+	} else if ib, err := fb.Stat(); err != nil {
+		return false, err
+	} else if ia.Size() != ib.Size() {
+		return false, nil
+	}
+
+	bufa := make([]byte, equalChunkSize)
+	bufb := make([]byte, equalChunkSize)
+	for {
+		na, erra := io.ReadFull(fa, bufa)
+		if erra != nil && erra != io.EOF && erra != io.ErrUnexpectedEOF {
+			return false, erra
+		}
+
+		nb, errb := io.ReadFull(fb, bufb)
+		if errb != nil && errb != io.EOF && errb != io.ErrUnexpectedEOF {
+			return false, errb
+		}
+
+		if na != nb || !bytes.Equal(bufa[:na], bufb[:nb]) {
+			return false, nil
+		} else if erra != nil || errb != nil { // Both reach the end.
+			return erra != nil && errb != nil, nil
+		}
+	}
+}
+
+// FilesEqualHash is the same as FilesEqual, but compares the SHA256 hashes
+// of the two files, which reads each file only once without comparing them
+// byte by byte.
+//
+// Notice: it's a probabilistic check, though the collision of SHA256
+// is practically impossible.
+func FilesEqualHash(a, b string) (bool, error) {
+	ha, err := fileSHA256(a)
+	if err != nil {
+		return false, err
+	}
+
+	hb, err := fileSHA256(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(ha, hb), nil
+}
+
+func fileSHA256(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
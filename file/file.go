@@ -2,6 +2,7 @@
 package file
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +19,12 @@ const (
 	DirType
 )
 
+// ErrNotDir is returned when the path is not a directory.
+var ErrNotDir = errors.New("the path is not a directory")
+
+// ErrBinaryFile is returned when the file is binary but a text is expected.
+var ErrBinaryFile = errors.New("the file is binary")
+
 // HomeDir is the home directory of the current user.
 var HomeDir = GetHomeDir()
 
@@ -146,11 +153,9 @@ func GetHomeDir() string {
 	return ""
 }
 
-// Abs is similar to Abs in the std library "path/filepath",
-// but firstly convert "~"" and "$HOME" to the home directory.
-//
-// Return the origin path if there is an error.
-func Abs(p string) string {
+// ExpandHome converts the leading "~" or "$HOME" of the path p
+// to the home directory.
+func ExpandHome(p string) string {
 	if p != "" && HomeDir != "" {
 		if p[0] == '~' {
 			p = strings.Replace(p, "~", HomeDir, 1)
@@ -158,7 +163,15 @@ func Abs(p string) string {
 			p = strings.Replace(p, "$HOME", HomeDir, 1)
 		}
 	}
+	return p
+}
 
+// Abs is similar to Abs in the std library "path/filepath",
+// but firstly convert "~"" and "$HOME" to the home directory.
+//
+// Return the origin path if there is an error.
+func Abs(p string) string {
+	p = ExpandHome(p)
 	if _p, err := filepath.Abs(p); err == nil {
 		return _p
 	}
@@ -175,9 +188,17 @@ func SelfDir() string {
 	return filepath.Dir(SelfPath())
 }
 
-// EnsureDir make the directory if not exist.
-func EnsureDir(fp string) error {
-	return os.MkdirAll(fp, os.ModePerm)
+// EnsureDir makes the directory tree with the permission perm if not exist.
+//
+// Return ErrNotDir if the path exists but is not a directory.
+func EnsureDir(dir string, perm os.FileMode) error {
+	if fi, err := os.Stat(dir); err == nil {
+		if !fi.IsDir() {
+			return ErrNotDir
+		}
+		return nil
+	}
+	return os.MkdirAll(dir, perm)
 }
 
 // SearchFile searches a file in paths.
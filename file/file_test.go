@@ -0,0 +1,406 @@
+package file
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnsureDir(t *testing.T) {
+	root := t.TempDir()
+
+	dir := filepath.Join(root, "a", "b", "c")
+	if err := EnsureDir(dir, 0755); err != nil {
+		t.Fatal(err)
+	} else if !IsDir(dir) {
+		t.Errorf("expected the directory '%s' to be created", dir)
+	}
+
+	if err := EnsureDir(dir, 0755); err != nil {
+		t.Errorf("expected no error for the existing directory, but got %s", err)
+	}
+
+	fp := filepath.Join(root, "file")
+	if err := ioutil.WriteFile(fp, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureDir(fp, 0755); err != ErrNotDir {
+		t.Errorf("expected ErrNotDir, but got %v", err)
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	base := "/var/log/app"
+	for _, p := range []string{"a.log", "sub/a.log", "./a.log", "sub/../a.log", "", "."} {
+		if joined, err := SafeJoin(base, p); err != nil {
+			t.Errorf("'%s': unexpected error: %s", p, err)
+		} else if joined != filepath.Join(base, p) {
+			t.Errorf("'%s': unexpected path '%s'", p, joined)
+		}
+	}
+
+	for _, p := range []string{"..", "../a.log", "sub/../../a.log", "../app2/a.log",
+		"/etc/passwd", "../../../../etc/passwd"} {
+		if joined, err := SafeJoin(base, p); err != ErrPathEscape {
+			t.Errorf("'%s': expected ErrPathEscape, but got '%s', %v", p, joined, err)
+		}
+	}
+
+	if joined, err := SafeJoin("/", "etc/passwd"); err != nil || joined != "/etc/passwd" {
+		t.Errorf("unexpected the joined path under the root: '%s', %v", joined, err)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watched")
+	if err := ioutil.WriteFile(path, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan struct{}, 10)
+	stop := WatchFile(path, 10*time.Millisecond, func() { changes <- struct{}{} })
+	defer stop()
+
+	wait := func(step string) {
+		select {
+		case <-changes:
+		case <-time.After(time.Second):
+			t.Fatalf("%s: no change is notified", step)
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	ioutil.WriteFile(path, []byte("ab"), 0644)
+	wait("modify")
+
+	os.Remove(path)
+	time.Sleep(30 * time.Millisecond)
+	select {
+	case <-changes:
+		t.Fatal("unexpected the notification of the deletion")
+	default:
+	}
+
+	ioutil.WriteFile(path, []byte("abc"), 0644)
+	wait("recreate")
+
+	stop()
+	ioutil.WriteFile(path, []byte("abcd"), 0644)
+	time.Sleep(30 * time.Millisecond)
+	if len(changes) != 0 {
+		t.Error("unexpected the notification after stopping")
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	dir := t.TempDir()
+	for i, c := range []struct {
+		content string
+		lines   int64
+	}{
+		{"", 0},
+		{"\n", 1},
+		{"a", 1},
+		{"a\nb\n", 2},
+		{"a\nb", 2},
+		{"a\n\nb\n\n", 4},
+	} {
+		path := filepath.Join(dir, "lines")
+		if err := ioutil.WriteFile(path, []byte(c.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if lines, err := CountLines(path); err != nil {
+			t.Errorf("%d: %v", i, err)
+		} else if lines != c.lines {
+			t.Errorf("%d: expected %d lines, but got %d", i, c.lines, lines)
+		}
+	}
+
+	if _, err := CountLines(filepath.Join(dir, "nonexistent")); err == nil {
+		t.Error("expected an error for the nonexistent file")
+	}
+}
+
+func TestOpenAppendAndTruncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "open")
+	for _, data := range []string{"a", "b"} {
+		f, err := OpenAppend(path, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(data)
+		f.Close()
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "ab" {
+		t.Errorf("expected 'ab', but got '%s'", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the permission 0600, but got %s", info.Mode().Perm())
+	}
+
+	f, err := OpenTruncate(path, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("c")
+	f.Close()
+	if data, _ := ioutil.ReadFile(path); string(data) != "c" {
+		t.Errorf("expected 'c', but got '%s'", data)
+	}
+}
+
+func TestReplaceInFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	ioutil.WriteFile(path, []byte("a=1\nb=1\nc=1\n"), 0600)
+
+	if n, err := ReplaceInFile(path, "=1", "=2", false); err != nil || n != 1 {
+		t.Errorf("expected 1 replacement, but got %d, %v", n, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "a=2\nb=1\nc=1\n" {
+		t.Errorf("unexpected the content: %q", data)
+	}
+
+	if n, err := ReplaceInFile(path, "=1", "=3", true); err != nil || n != 2 {
+		t.Errorf("expected 2 replacements, but got %d, %v", n, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "a=2\nb=3\nc=3\n" {
+		t.Errorf("unexpected the content: %q", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the permission 0600, but got %s", info.Mode().Perm())
+	}
+
+	if n, err := ReplaceInFile(path, "x", "y", true); err != nil || n != 0 {
+		t.Errorf("expected no replacement, but got %d, %v", n, err)
+	}
+	if names, _ := ListDir2(filepath.Dir(path)); len(names) != 1 {
+		t.Errorf("unexpected the temporary files: %v", names)
+	}
+
+	binary := filepath.Join(filepath.Dir(path), "binary")
+	ioutil.WriteFile(binary, []byte("a\x00b"), 0644)
+	if _, err := ReplaceInFile(binary, "a", "b", true); err != ErrBinaryFile {
+		t.Errorf("expected ErrBinaryFile, but got %v", err)
+	}
+}
+
+func TestWriteFileIfChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "generated")
+	if changed, err := WriteFileIfChanged(path, []byte("v1"), 0644); err != nil || !changed {
+		t.Fatalf("expected to write the new file, but got %v, %v", changed, err)
+	}
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(path, old, old)
+	if changed, err := WriteFileIfChanged(path, []byte("v1"), 0644); err != nil || changed {
+		t.Errorf("expected not to write the unchanged file, but got %v, %v", changed, err)
+	}
+	if info, _ := os.Stat(path); !info.ModTime().Equal(old) {
+		t.Errorf("expected the unchanged mtime %s, but got %s", old, info.ModTime())
+	}
+
+	if changed, err := WriteFileIfChanged(path, []byte("v2"), 0644); err != nil || !changed {
+		t.Errorf("expected to write the changed file, but got %v, %v", changed, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "v2" {
+		t.Errorf("unexpected the content: %q", data)
+	}
+}
+
+func TestFilesEqual(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		ioutil.WriteFile(path, data, 0644)
+		return path
+	}
+
+	large := make([]byte, 3*equalChunkSize+100)
+	for i := range large {
+		large[i] = byte(i)
+	}
+	changed := append([]byte{}, large...)
+	changed[len(changed)-1]++
+
+	src := write("src", large)
+	tests := []struct {
+		path  string
+		equal bool
+	}{
+		{write("same", large), true},
+		{write("changed", changed), false},
+		{write("short", large[:len(large)-1]), false},
+		{write("empty", nil), false},
+	}
+
+	for _, test := range tests {
+		if equal, err := FilesEqual(src, test.path); err != nil || equal != test.equal {
+			t.Errorf("%s: expected %v, but got %v, %v", test.path, test.equal, equal, err)
+		}
+		if equal, err := FilesEqualHash(src, test.path); err != nil || equal != test.equal {
+			t.Errorf("%s: expected hash %v, but got %v, %v", test.path, test.equal, equal, err)
+		}
+	}
+
+	if _, err := FilesEqual(src, filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected the not-exist error, but got %v", err)
+	}
+	if _, err := FilesEqualHash(src, filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected the not-exist error for hash, but got %v", err)
+	}
+}
+
+func TestAppendRotating(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.log")
+	for _, line := range []string{"run 1\n", "run 2\n", "run 3\n", "run 4\n"} {
+		if err := AppendRotating(path, line, 8, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]string{
+		path:        "",
+		path + ".1": "run 3\nrun 4\n",
+		path + ".2": "run 1\nrun 2\n",
+	}
+	for name, content := range expected {
+		if data, err := ioutil.ReadFile(name); content == "" {
+			if !os.IsNotExist(err) {
+				t.Errorf("expected '%s' not to exist, but got %q, %v", name, data, err)
+			}
+		} else if string(data) != content {
+			t.Errorf("%s: expected %q, but got %q", name, content, data)
+		}
+	}
+
+	if err := AppendRotating(path, "run 5\nrun 6\n", 8, 2); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(path + ".2"); string(data) != "run 3\nrun 4\n" {
+		t.Errorf("expected the oldest backup to be dropped, but got %q", data)
+	}
+	if IsExist(path + ".3") {
+		t.Errorf("expected no more than 2 backups")
+	}
+}
+
+func TestReadFirstBytesAndLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	ioutil.WriteFile(path, []byte("line 1\r\nline 2\n\nline 4"), 0644)
+
+	if data, err := ReadFirstBytes(path, 4); err != nil || string(data) != "line" {
+		t.Errorf("expected %q, but got %q, %v", "line", data, err)
+	}
+	if data, err := ReadFirstBytes(path, 100); err != nil || len(data) != 22 {
+		t.Errorf("expected the whole content, but got %q, %v", data, err)
+	}
+
+	tests := []struct {
+		n        int
+		expected []string
+	}{
+		{0, []string{}},
+		{2, []string{"line 1", "line 2"}},
+		{4, []string{"line 1", "line 2", "", "line 4"}},
+		{10, []string{"line 1", "line 2", "", "line 4"}},
+	}
+	for _, test := range tests {
+		if lines, err := ReadFirstLines(path, test.n); err != nil {
+			t.Errorf("%d: %v", test.n, err)
+		} else if !reflect.DeepEqual(lines, test.expected) {
+			t.Errorf("%d: expected %q, but got %q", test.n, test.expected, lines)
+		}
+	}
+
+	if data, err := ReadFirstBytes(path, math.MaxInt); err != nil || len(data) != 22 {
+		t.Errorf("expected the whole content, but got %q, %v", data, err)
+	}
+	if lines, err := ReadFirstLines(path, math.MaxInt); err != nil || len(lines) != 4 {
+		t.Errorf("expected all the lines, but got %q, %v", lines, err)
+	}
+
+	if _, err := ReadFirstLines(filepath.Join(t.TempDir(), "missing"), 1); !os.IsNotExist(err) {
+		t.Errorf("expected the not-exist error, but got %v", err)
+	}
+}
+
+func TestStatCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stat")
+	ioutil.WriteFile(path, []byte("data"), 0644)
+
+	c := NewStatCache(time.Hour)
+	if info, err := c.Stat(path); err != nil || info.Size() != 4 {
+		t.Fatalf("unexpected the result: %v, %v", info, err)
+	}
+
+	// Hit the cache within the TTL.
+	os.Remove(path)
+	if info, err := c.Stat(path); err != nil || info.Size() != 4 {
+		t.Errorf("expected the cached result, but got %v, %v", info, err)
+	}
+
+	// The error is cached as well.
+	c.Invalidate(path)
+	if _, err := c.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the not-exist error, but got %v", err)
+	}
+	ioutil.WriteFile(path, []byte("new data"), 0644)
+	if _, err := c.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the cached not-exist error, but got %v", err)
+	}
+
+	c.Clear()
+	if info, err := c.Stat(path); err != nil || info.Size() != 8 {
+		t.Errorf("expected the fresh result, but got %v, %v", info, err)
+	}
+
+	// Miss the cache after expiring.
+	c = NewStatCache(10 * time.Millisecond)
+	c.Stat(path)
+	os.Remove(path)
+	time.Sleep(20 * time.Millisecond)
+	if _, err := c.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the not-exist error after expiring, but got %v", err)
+	}
+}
+
+func TestTempFileAndDir(t *testing.T) {
+	root := t.TempDir()
+
+	f, cleanup, err := TempFile(root, "tmp-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := f.Name()
+	if filepath.Dir(name) != root || !IsFile(name) {
+		t.Errorf("unexpected the temporary file '%s'", name)
+	}
+	cleanup()
+	if _, err := f.WriteString("closed"); err == nil {
+		t.Error("expected the temporary file to be closed")
+	}
+	if IsExist(name) {
+		t.Errorf("expected the temporary file '%s' to be removed", name)
+	}
+
+	dir, cleanup, err := TempDir(root, "tmp-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(dir, "a", "b")
+	EnsureDir(sub, 0755)
+	ioutil.WriteFile(filepath.Join(sub, "file"), []byte("data"), 0644)
+	cleanup()
+	if IsExist(dir) {
+		t.Errorf("expected the temporary directory '%s' to be removed", dir)
+	}
+
+	if _, _, err := TempFile(filepath.Join(root, "missing"), "tmp"); err == nil {
+		t.Error("expected the error for the missing directory")
+	}
+}
@@ -0,0 +1,43 @@
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPathEscape is returned when the path escapes from the base directory.
+var ErrPathEscape = errors.New("the path escapes from the base directory")
+
+// SafeJoin joins the trusted base directory and the untrusted relative path
+// userPath, such as from the HTTP parameter, and returns the cleaned absolute
+// path, which is used to prevent the directory traversal attack.
+//
+// Return ErrPathEscape if userPath is an absolute path, or the result escapes
+// from base, for example, by "..".
+//
+// Notice: the symbolic links are not resolved, so the symbolic link under
+// base pointing to the outside is not detected.
+func SafeJoin(base, userPath string) (string, error) {
+	if filepath.IsAbs(userPath) || filepath.VolumeName(userPath) != "" ||
+		strings.HasPrefix(userPath, "/") || strings.HasPrefix(userPath, `\`) {
+		return "", ErrPathEscape
+	}
+
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+
+	joined := filepath.Join(base, userPath)
+	prefix := base
+	if !strings.HasSuffix(prefix, string(os.PathSeparator)) {
+		prefix += string(os.PathSeparator)
+	}
+
+	if joined != base && !strings.HasPrefix(joined, prefix) {
+		return "", ErrPathEscape
+	}
+	return joined, nil
+}
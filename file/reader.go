@@ -2,7 +2,10 @@ package file
 
 import (
 	"bufio"
+	"bytes"
+	"io"
 	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -70,3 +73,92 @@ func ReadLine(r *bufio.Reader) ([]byte, error) {
 
 	return line, err
 }
+
+// CountLines returns the number of the lines in the file, which streams
+// through the file by counting the newlines.
+//
+// The final line without the trailing newline is counted as a line,
+// and the empty file has no line.
+func CountLines(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var lines int64
+	last := byte('\n')
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			lines += int64(bytes.Count(buf[:n], []byte{'\n'}))
+			last = buf[n-1]
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	if last != '\n' {
+		lines++
+	}
+	return lines, nil
+}
+
+// ReadFirstBytes reads the first n bytes of the file at most, which doesn't
+// read the rest of the file, such as detecting the format by the header.
+//
+// If the file is shorter than n bytes, return the whole content without error.
+func ReadFirstBytes(path string, n int) ([]byte, error) {
+	if n < 0 {
+		n = 0
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Not allocate n bytes in advance, because n may be much bigger than
+	// the file, such as math.MaxInt.
+	return ioutil.ReadAll(io.LimitReader(f, int64(n)))
+}
+
+// ReadFirstLines reads the first n lines of the file at most, which doesn't
+// read the rest of the file, such as previewing the large log file.
+// The trailing "\n" or "\r\n" of each line is removed.
+//
+// If the file has fewer than n lines, return all the lines without error.
+func ReadFirstLines(path string, n int) ([]string, error) {
+	if n < 0 {
+		n = 0
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := make([]string, 0, min(n, 64))
+	r := bufio.NewReader(f)
+	for len(lines) < n {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			line = strings.TrimSuffix(line, "\n")
+			lines = append(lines, strings.TrimSuffix(line, "\r"))
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
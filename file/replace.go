@@ -0,0 +1,50 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+)
+
+// ReplaceInFile replaces the occurrences of old with new in the text file,
+// only the first one if all is false, and returns the number of
+// the replacements.
+//
+// The file is written back atomically by the temporary file and renaming,
+// with the same permission, so a crash doesn't leave a half-edited file.
+// And it's not written if there is no occurrence.
+//
+// Return ErrBinaryFile if the file contains the NUL byte, which is rejected
+// as the binary file.
+func ReplaceInFile(path string, old, new string, all bool) (int, error) {
+	if old == "" {
+		return 0, errors.New("the replaced string must not be empty")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	} else if bytes.IndexByte(data, 0) > -1 {
+		return 0, ErrBinaryFile
+	}
+
+	content := string(data)
+	n := strings.Count(content, old)
+	if n == 0 {
+		return 0, nil
+	} else if !all {
+		n = 1
+	}
+
+	content = strings.Replace(content, old, new, n)
+	if err = writeFileAtomic(path, []byte(content), info.Mode().Perm()); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
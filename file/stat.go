@@ -0,0 +1,64 @@
+package file
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+type statResult struct {
+	info   os.FileInfo
+	err    error
+	expire time.Time
+}
+
+// StatCache caches the result of os.Stat, including the error, for a TTL,
+// which reduces the syscalls when stating the same files repeatedly,
+// such as scanning the files in a hot path.
+//
+// Notice: it trades the freshness for the speed, so don't use it when
+// the files change rapidly within the TTL.
+type StatCache struct {
+	sync.Mutex
+	ttl   time.Duration
+	cache map[string]statResult
+}
+
+// NewStatCache returns a new StatCache, which caches the result for ttl.
+func NewStatCache(ttl time.Duration) *StatCache {
+	return &StatCache{ttl: ttl, cache: make(map[string]statResult)}
+}
+
+// Stat is the same as os.Stat, but returns the cached result if not expired.
+func (c *StatCache) Stat(path string) (os.FileInfo, error) {
+	now := time.Now()
+
+	c.Lock()
+	r, ok := c.cache[path]
+	c.Unlock()
+	if ok && now.Before(r.expire) {
+		return r.info, r.err
+	}
+
+	r.info, r.err = os.Stat(path)
+	r.expire = now.Add(c.ttl)
+
+	c.Lock()
+	c.cache[path] = r
+	c.Unlock()
+	return r.info, r.err
+}
+
+// Invalidate removes the cached result of the path.
+func (c *StatCache) Invalidate(path string) {
+	c.Lock()
+	delete(c.cache, path)
+	c.Unlock()
+}
+
+// Clear removes all the cached results.
+func (c *StatCache) Clear() {
+	c.Lock()
+	c.cache = make(map[string]statResult)
+	c.Unlock()
+}
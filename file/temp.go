@@ -0,0 +1,25 @@
+package file
+
+import "os"
+
+// TempFile is the same as os.CreateTemp, but also returns the cleanup
+// function, which closes and removes the temporary file.
+//
+// So you can defer the cleanup function without reconstructing the path.
+func TempFile(dir, pattern string) (*os.File, func(), error) {
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close(); os.Remove(f.Name()) }, nil
+}
+
+// TempDir is the same as os.MkdirTemp, but also returns the cleanup
+// function, which removes the temporary directory and all it contains.
+func TempDir(dir, pattern string) (string, func(), error) {
+	name, err := os.MkdirTemp(dir, pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, func() { os.RemoveAll(name) }, nil
+}
@@ -0,0 +1,60 @@
+package file
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+type fileState struct {
+	exist bool
+	size  int64
+	mtime int64
+}
+
+func statFile(path string) (s fileState) {
+	if info, err := os.Stat(path); err == nil {
+		s = fileState{exist: true, size: info.Size(), mtime: info.ModTime().UnixNano()}
+	}
+	return
+}
+
+// WatchFile polls the modification time and size of the file every interval,
+// and calls onChange when either of them differs from the last observation,
+// which is portable without the notification of the filesystem.
+//
+// The deleted file is not considered as changed until it appears again,
+// which is a change. The returned function stops watching, and waits for
+// onChange to return if it's being called.
+func WatchFile(path string, interval time.Duration, onChange func()) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := statFile(path)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				current := statFile(path)
+				if current != last {
+					last = current
+					if current.exist {
+						onChange()
+					}
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+}
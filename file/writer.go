@@ -1,8 +1,11 @@
 package file
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 )
 
 // WriteBytes writes the byte content to a file.
@@ -23,3 +26,113 @@ func WriteBytes(filePath string, b []byte) (int, error) {
 func WriteString(filePath string, s string) (int, error) {
 	return WriteBytes(filePath, []byte(s))
 }
+
+// OpenAppend opens the file to append the data only for writing,
+// which is created with the permission perm if not exist.
+func OpenAppend(path string, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perm)
+}
+
+// OpenTruncate opens the file only for writing and truncates it,
+// which is created with the permission perm if not exist.
+func OpenTruncate(path string, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(path, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, perm)
+}
+
+// writeFileAtomic writes the data into the temporary file in the same
+// directory as path, then renames it to path, so path has either the old
+// or the new content, even if crashing.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	f, cleanup, err := TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			cleanup()
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		return
+	} else if err = f.Sync(); err != nil {
+		return
+	} else if err = f.Close(); err != nil {
+		return
+	} else if err = os.Chmod(f.Name(), perm); err != nil {
+		return
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// WriteFileIfChanged writes the data into the file atomically only if
+// the content is different, and returns true if written. The permission
+// perm is used only when writing the file.
+//
+// So the modification time is not touched if the content is unchanged,
+// which avoids triggering the watchers, such as for the generated files.
+func WriteFileIfChanged(path string, data []byte, perm os.FileMode) (changed bool, err error) {
+	old, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(old, data) {
+		return false, nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	if err = writeFileAtomic(path, data, perm); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AppendRotating appends the data to the file, which is created with
+// the permission 0644 if not exist, then rotates the file if its size
+// exceeds maxSize, like the numbered rotation of the sized rotating handler,
+// that's, "path" is renamed to "path.1", "path.1" to "path.2", and so on,
+// and the oldest one beyond backups is removed. If backups is 0,
+// the file is removed instead of being kept as a backup.
+//
+// It's stateless, which is convenient for the scripts appending the file
+// occasionally without keeping a handler around, such as the cron jobs.
+//
+// Notice: it opens and stats the file on each call, so it's not suitable for
+// the high-frequency writes.
+func AppendRotating(path, data string, maxSize int64, backups int) (err error) {
+	f, err := OpenAppend(path, 0644)
+	if err != nil {
+		return
+	}
+
+	if _, err = f.WriteString(data); err != nil {
+		f.Close()
+		return
+	}
+
+	fi, err := f.Stat()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || fi.Size() <= maxSize {
+		return
+	}
+	return rotateNumbered(path, backups)
+}
+
+// rotateNumbered renames path to "path.1" by shifting the numbered backups,
+// and removes the oldest one beyond backups.
+func rotateNumbered(path string, backups int) error {
+	if backups <= 0 {
+		return os.Remove(path)
+	}
+
+	if err := os.Remove(fmt.Sprintf("%s.%d", path, backups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := backups - 1; i > 0; i-- {
+		src := fmt.Sprintf("%s.%d", path, i)
+		if err := os.Rename(src, fmt.Sprintf("%s.%d", path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(path, path+".1")
+}
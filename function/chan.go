@@ -0,0 +1,40 @@
+package function
+
+// SliceToChan streams the elements of the slice or array into the returned
+// channel with the buffer size in a new goroutine, and closes the channel
+// after all the elements are sent.
+//
+// If slice is not a slice or array type, it will panic.
+func SliceToChan(slice interface{}, buffer int) <-chan interface{} {
+	return SliceToChanDone(slice, buffer, nil)
+}
+
+// SliceToChanDone is the same as SliceToChan, but stops sending the rest
+// elements and closes the channel when done is closed, so that
+// the goroutine does not leak if the consumer aborts.
+//
+// If done is nil, it's the same as SliceToChan.
+func SliceToChanDone(slice interface{}, buffer int, done <-chan struct{}) <-chan interface{} {
+	values := interfaces(slice)
+	out := make(chan interface{}, buffer)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			select {
+			case out <- v:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ChanToSlice drains the channel into a slice until it's closed.
+func ChanToSlice(ch <-chan interface{}) []interface{} {
+	values := make([]interface{}, 0, len(ch))
+	for v := range ch {
+		values = append(values, v)
+	}
+	return values
+}
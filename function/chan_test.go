@@ -0,0 +1,40 @@
+package function
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSliceToChan(t *testing.T) {
+	values := ChanToSlice(SliceToChan([]int{1, 2, 3}, 0))
+	if expected := []interface{}{1, 2, 3}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, but got %v", expected, values)
+	}
+
+	if values = ChanToSlice(SliceToChan([]string{}, 1)); len(values) != 0 {
+		t.Errorf("expected no values, but got %v", values)
+	}
+
+	double := Stage(func(v interface{}) interface{} { return v.(int) * 2 })
+	values = ChanToSlice(Pipeline(double, double)(SliceToChan([]int{1, 2, 3}, 3)))
+	if expected := []interface{}{4, 8, 12}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, but got %v", expected, values)
+	}
+}
+
+func TestSliceToChanDone(t *testing.T) {
+	done := make(chan struct{})
+	ch := SliceToChanDone(Range(0, 100, 1), 0, done)
+	if v := <-ch; v != 0 {
+		t.Errorf("expected 0, but got %v", v)
+	}
+	close(done)
+
+	var n int
+	for range ch {
+		n++
+	}
+	if n > 1 {
+		t.Errorf("expected to abort, but got %d more values", n)
+	}
+}
@@ -0,0 +1,93 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ClampValue returns low if v is less than low, high if v is greater than
+// high, or v itself, which are compared by Compare.
+//
+// If low is greater than high, or the values cannot be compared by Compare,
+// it will panic.
+func ClampValue(v, low, high interface{}) interface{} {
+	if Compare(low, high) > 0 {
+		panic(fmt.Errorf("the low %v is greater than the high %v", low, high))
+	}
+
+	if Compare(v, low) < 0 {
+		return low
+	} else if Compare(v, high) > 0 {
+		return high
+	}
+	return v
+}
+
+// ClampAll returns a new slice, each element of which is the one of slice
+// clamped into [low, high] by ClampValue. The type of the new slice is the
+// same as slice, but it's a slice with the same element type if slice is
+// an array.
+//
+// If slice is not a slice or array type, or any element cannot be clamped
+// by ClampValue, it will panic.
+func ClampAll(slice interface{}, low, high interface{}) interface{} {
+	vs := reflect.ValueOf(slice)
+	switch vs.Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		panic(ErrNotSliceOrArray)
+	}
+
+	_type := vs.Type()
+	if _type.Kind() == reflect.Array {
+		_type = reflect.SliceOf(_type.Elem())
+	}
+
+	_len := vs.Len()
+	result := reflect.MakeSlice(_type, _len, _len)
+	for i := 0; i < _len; i++ {
+		v := ClampValue(vs.Index(i).Interface(), low, high)
+		result.Index(i).Set(reflect.ValueOf(v))
+	}
+	return result.Interface()
+}
+
+// ClampSlice clamps every element of slice into [lo, hi] in place
+// by ClampValue, such as pinning the out-of-range readings to the bounds.
+//
+// If slice is not a slice of numbers, or lo is greater than hi, it will panic.
+func ClampSlice(slice interface{}, lo, hi interface{}) {
+	vs := reflect.ValueOf(slice)
+	if vs.Kind() != reflect.Slice {
+		panic(fmt.Errorf("the value is not a slice: %T", slice))
+	}
+	mustNumericElem(vs.Type())
+
+	for i, _len := 0, vs.Len(); i < _len; i++ {
+		v := vs.Index(i)
+		v.Set(reflect.ValueOf(ClampValue(v.Interface(), lo, hi)))
+	}
+}
+
+// ClampedSlice is the same as ClampSlice, but returns a new clamped slice
+// by ClampAll and leaves slice unchanged, which may be an array.
+func ClampedSlice(slice interface{}, lo, hi interface{}) interface{} {
+	vs := reflect.ValueOf(slice)
+	switch vs.Kind() {
+	case reflect.Slice, reflect.Array:
+		mustNumericElem(vs.Type())
+	default:
+		panic(ErrNotSliceOrArray)
+	}
+	return ClampAll(slice, lo, hi)
+}
+
+func mustNumericElem(_type reflect.Type) {
+	switch _type.Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+	default:
+		panic(fmt.Errorf("the element is not a number: %s", _type.Elem()))
+	}
+}
@@ -0,0 +1,84 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleClampValue() {
+	fmt.Println(ClampValue(-1, 0, 10))
+	fmt.Println(ClampValue(5, 0, 10))
+	fmt.Println(ClampValue(11, 0, 10))
+
+	// Output:
+	// 0
+	// 5
+	// 10
+}
+
+func ExampleClampAll() {
+	fmt.Println(ClampAll([]int{-5, 0, 3, 10, 15}, 0, 10))
+	fmt.Println(ClampAll([]float64{-0.5, 0.25, 1.5}, 0.0, 1.0))
+
+	// Output:
+	// [0 0 3 10 10]
+	// [0 0.25 1]
+}
+
+func TestClampAll(t *testing.T) {
+	ints := []int{-5, 3, 15}
+	if result := ClampAll(ints, 0, 10); !reflect.DeepEqual(result, []int{0, 3, 10}) {
+		t.Errorf("expected %v, but got %v", []int{0, 3, 10}, result)
+	} else if !reflect.DeepEqual(ints, []int{-5, 3, 15}) {
+		t.Errorf("expected the origin slice not to be changed, but got %v", ints)
+	}
+
+	array := [3]float64{-1, 0.5, 2}
+	if result := ClampAll(array, 0.0, 1.0); !reflect.DeepEqual(result, []float64{0, 0.5, 1}) {
+		t.Errorf("expected %v, but got %v", []float64{0, 0.5, 1}, result)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected a panic when the low is greater than the high")
+			}
+		}()
+		ClampAll([]int{1}, 10, 0)
+	}()
+}
+
+func ExampleClampSlice() {
+	readings := []float64{-3.5, 12.25, 40, 101}
+	ClampSlice(readings, 0.0, 100.0)
+	fmt.Println(readings)
+
+	// Output:
+	// [0 12.25 40 100]
+}
+
+func TestClampedSlice(t *testing.T) {
+	ints := []int{-5, 3, 15}
+	if result := ClampedSlice(ints, 0, 10); !reflect.DeepEqual(result, []int{0, 3, 10}) {
+		t.Errorf("expected %v, but got %v", []int{0, 3, 10}, result)
+	} else if !reflect.DeepEqual(ints, []int{-5, 3, 15}) {
+		t.Errorf("expected the origin slice not to be changed, but got %v", ints)
+	}
+
+	for _, f := range []func(){
+		func() { ClampSlice([]string{"a"}, "a", "b") },
+		func() { ClampedSlice([]string{"a"}, "a", "b") },
+		func() { ClampSlice([]int{1}, 10, 0) },
+		func() { ClampSlice([1]int{1}, 0, 10) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
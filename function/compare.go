@@ -1,26 +1,50 @@
 package function
 
-import "strings"
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+)
 
 // Compare whether v1 is greater than v2.
 // Return a positive integer if greater, 0 if equal, a negative if less.
 //
 // v1 and v2 may be a byte, rune, int, uint, int8, int16, int32, int64,
 // uint8, uint16, uint32, uint64, float32, float64, string, or their slice,
-// or a struct implementing the interface of Comparer.
+// time.Duration, net.IP, or a struct implementing the interface of Comparer.
+// Other types are compared by CompareReflect, which compares the pointers by
+// dereferencing them recursively, and the nil pointer is less than
+// the non-nil one.
 //
-// Notice: if the types of v1 and v2 are not identical, it will panic.
+// Notice: if the types of v1 and v2 are not identical, or they cannot be
+// compared, it will panic.
 func Compare(v1, v2 interface{}) int {
+	r, err := CompareE(v1, v2)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// CompareE is the same as Compare, but returns an error instead of panicking.
+func CompareE(v1, v2 interface{}) (int, error) {
 	if _v1, ok := v1.(Comparer); ok {
-		return _v1.Compare(v2)
+		return _v1.Compare(v2), nil
+	}
+
+	if reflect.TypeOf(v1) != reflect.TypeOf(v2) {
+		return 0, fmt.Errorf("the types are not identical: %T and %T", v1, v2)
 	}
 
+	// The integers which may exceed 2^53 are compared exactly, not by float64.
 	var first, second float64
 	switch _v1 := v1.(type) {
 	case int:
-		first, second = float64(_v1), float64(v2.(int))
+		return orderedComparer[int](_v1, v2), nil
 	case uint:
-		first, second = float64(_v1), float64(v2.(uint))
+		return orderedComparer[uint](_v1, v2), nil
 	case int8:
 		first, second = float64(_v1), float64(v2.(int8))
 	case uint8:
@@ -34,25 +58,32 @@ func Compare(v1, v2 interface{}) int {
 	case uint16:
 		first, second = float64(_v1), float64(v2.(uint16))
 	case int64:
-		first, second = float64(_v1), float64(v2.(int64))
+		return orderedComparer[int64](_v1, v2), nil
 	case uint64:
-		first, second = float64(_v1), float64(v2.(uint64))
+		return orderedComparer[uint64](_v1, v2), nil
 	case float32:
 		first, second = float64(_v1), float64(v2.(float32))
 	case float64:
 		first, second = _v1, v2.(float64)
 	case string:
-		return strings.Compare(_v1, v2.(string))
+		return strings.Compare(_v1, v2.(string)), nil
+	case time.Duration:
+		return CompareTimeDuration(_v1, v2.(time.Duration)), nil
+	case net.IP:
+		return CompareIP(_v1, v2.(net.IP)), nil
 	default:
-		return compareSlice(v1, v2)
+		if r, ok := compareSlice(v1, v2); ok {
+			return r, nil
+		}
+		return CompareReflect(v1, v2)
 	}
 
 	if first > second {
-		return 1
+		return 1, nil
 	} else if first < second {
-		return -1
+		return -1, nil
 	} else {
-		return 0
+		return 0, nil
 	}
 }
 
@@ -0,0 +1,19 @@
+package function
+
+// CompareBoolPtr compares the tri-state booleans a and b, which are ordered
+// as nil (unset) < false < true. It returns -1 if a is less than b, 1 if
+// a is greater than b, or 0 if they are equal.
+func CompareBoolPtr(a, b *bool) int {
+	return compareLen(boolPtrRank(a), boolPtrRank(b))
+}
+
+func boolPtrRank(b *bool) int {
+	switch {
+	case b == nil:
+		return 0
+	case *b:
+		return 2
+	default:
+		return 1
+	}
+}
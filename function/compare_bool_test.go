@@ -0,0 +1,21 @@
+package function
+
+import "testing"
+
+func TestCompareBoolPtr(t *testing.T) {
+	f, tr := false, true
+	values := []*bool{nil, &f, &tr} // In the ascending order.
+	for i, a := range values {
+		for j, b := range values {
+			expected := compareLen(i, j)
+			if r := CompareBoolPtr(a, b); r != expected {
+				t.Errorf("%d <=> %d: expected %d, but got %d", i, j, expected, r)
+			}
+		}
+	}
+
+	f2 := false
+	if r := CompareBoolPtr(&f, &f2); r != 0 {
+		t.Errorf("expected the different pointers to false to be equal, but got %d", r)
+	}
+}
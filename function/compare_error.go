@@ -0,0 +1,27 @@
+package function
+
+import (
+	"errors"
+	"strings"
+)
+
+// CompareError compares the errors a and b, which returns -1 if a is less
+// than b, 1 if a is greater than b, or 0 if they are equal.
+//
+// nil is less than any non-nil error, and both nil are equal. Or, they are
+// equal if either matches the other by errors.Is, such as the wrapped error
+// and the one wrapped by it, else they are compared by the messages.
+func CompareError(a, b error) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case errors.Is(a, b) || errors.Is(b, a):
+		return 0
+	default:
+		return strings.Compare(a.Error(), b.Error())
+	}
+}
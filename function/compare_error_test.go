@@ -0,0 +1,35 @@
+package function
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCompareError(t *testing.T) {
+	errA := errors.New("a error")
+	errB := errors.New("b error")
+	wrapped := fmt.Errorf("context: %w", errB)
+
+	tests := []struct {
+		a, b     error
+		expected int
+	}{
+		{nil, nil, 0},
+		{nil, errA, -1},
+		{errA, nil, 1},
+		{errA, errA, 0},
+		{wrapped, errB, 0},
+		{errB, wrapped, 0},
+		{errA, errB, -1},
+		{errB, errA, 1},
+		{errA, errors.New("a error"), 0},
+		{wrapped, errA, 1},
+	}
+
+	for i, test := range tests {
+		if r := CompareError(test.a, test.b); r != test.expected {
+			t.Errorf("%d: expected %d, but got %d", i, test.expected, r)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package function
+
+import (
+	"cmp"
+	"net"
+	"time"
+)
+
+// ComparerFor returns a comparison function specialized for the type of
+// sample, which is chosen only once, so it avoids the type switch and
+// the reflection of Compare on each call in the hot loop.
+//
+// The returned function has the same result as Compare, but a and b must be
+// the same type as sample, or it will panic. For the type without
+// the specialization, it falls back to Compare.
+func ComparerFor(sample interface{}) func(a, b interface{}) int {
+	if _, ok := sample.(Comparer); ok {
+		return func(a, b interface{}) int { return a.(Comparer).Compare(b) }
+	}
+
+	switch sample.(type) {
+	case int:
+		return orderedComparer[int]
+	case uint:
+		return orderedComparer[uint]
+	case int8:
+		return orderedComparer[int8]
+	case uint8:
+		return orderedComparer[uint8]
+	case int16:
+		return orderedComparer[int16]
+	case uint16:
+		return orderedComparer[uint16]
+	case int32:
+		return orderedComparer[int32]
+	case uint32:
+		return orderedComparer[uint32]
+	case int64:
+		return orderedComparer[int64]
+	case uint64:
+		return orderedComparer[uint64]
+	case float32:
+		return orderedComparer[float32]
+	case float64:
+		return orderedComparer[float64]
+	case string:
+		return orderedComparer[string]
+	case time.Duration:
+		return orderedComparer[time.Duration]
+	case net.IP:
+		return func(a, b interface{}) int { return CompareIP(a.(net.IP), b.(net.IP)) }
+	case []int:
+		return func(a, b interface{}) int { return compareIntSlice(a.([]int), b.([]int)) }
+	case []int64:
+		return func(a, b interface{}) int { return compareInt64Slice(a.([]int64), b.([]int64)) }
+	case []uint8:
+		return func(a, b interface{}) int { return compareUint8Slice(a.([]uint8), b.([]uint8)) }
+	case []float64:
+		return func(a, b interface{}) int { return compareFloat64Slice(a.([]float64), b.([]float64)) }
+	case []string:
+		return func(a, b interface{}) int { return compareStringSlice(a.([]string), b.([]string)) }
+	default:
+		return Compare
+	}
+}
+
+// orderedComparer compares a and b like Compare, so NaN is equal to any float.
+func orderedComparer[T cmp.Ordered](a, b interface{}) int {
+	x, y := a.(T), b.(T)
+	if x < y {
+		return -1
+	} else if x > y {
+		return 1
+	}
+	return 0
+}
+
+// FromLess returns a three-way comparison function from the less function,
+// which returns -1 if a is less than b, 1 if b is less than a, or 0.
+// So it's able to be used by the comparator-based helpers, such as
+// slices.SortFunc, with the ordinary less function.
+//
+// less must be a strict weak ordering, then the returned function is
+// a consistent total order of the equivalence classes.
+func FromLess[T any](less func(a, b T) bool) func(a, b T) int {
+	return func(a, b T) int {
+		if less(a, b) {
+			return -1
+		} else if less(b, a) {
+			return 1
+		}
+		return 0
+	}
+}
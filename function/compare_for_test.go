@@ -0,0 +1,92 @@
+package function
+
+import (
+	"math"
+	"net"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestComparerFor(t *testing.T) {
+	cases := [][2]interface{}{
+		{1, 2},
+		{uint8(3), uint8(3)},
+		{int64(5), int64(-5)},
+		{int64(1<<53 + 1), int64(1 << 53)},
+		{uint64(math.MaxUint64), uint64(math.MaxUint64 - 1)},
+		{1.5, 0.5},
+		{float32(1), float32(2)},
+		{"abc", "abd"},
+		{time.Second, time.Millisecond},
+		{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")},
+		{[]int{1, 2}, []int{1}},
+		{[]string{"a"}, []string{"a", "b"}},
+		{[]byte("ab"), []byte("ab")},
+		{true, false},
+		{[2]int{1, 2}, [2]int{1, 3}},
+	}
+
+	// Not equal even if they are equal as float64.
+	if r := Compare(int64(1<<53+1), int64(1<<53)); r != 1 {
+		t.Errorf("expected 1, but got %d", r)
+	}
+
+	for _, c := range cases {
+		expected := Compare(c[0], c[1])
+		if r := ComparerFor(c[0])(c[0], c[1]); r != expected {
+			t.Errorf("%T: %v <=> %v: expected %d, but got %d", c[0], c[0], c[1], expected, r)
+		}
+		if r := ComparerFor(c[0])(c[1], c[0]); r != -expected {
+			t.Errorf("%T: %v <=> %v: expected %d, but got %d", c[0], c[1], c[0], -expected, r)
+		}
+	}
+}
+
+func BenchmarkCompare(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Compare(i, 1000)
+	}
+}
+
+func BenchmarkComparerFor(b *testing.B) {
+	compare := ComparerFor(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compare(i, 1000)
+	}
+}
+
+func TestFromLess(t *testing.T) {
+	type record struct {
+		Name string
+		Age  int
+	}
+
+	less := func(a, b record) bool { return a.Age < b.Age }
+	compare := FromLess(less)
+
+	records := []record{{"a", 30}, {"b", 20}, {"c", 30}, {"d", 10}}
+	for _, a := range records {
+		for _, b := range records {
+			r := compare(a, b)
+			switch {
+			case less(a, b) && r != -1, less(b, a) && r != 1:
+				t.Errorf("%v and %v: inconsistent with less, got %d", a, b, r)
+			case !less(a, b) && !less(b, a) && r != 0:
+				t.Errorf("%v and %v: expected 0, but got %d", a, b, r)
+			}
+
+			if r != -compare(b, a) {
+				t.Errorf("%v and %v: not antisymmetric", a, b)
+			}
+		}
+	}
+
+	slices.SortStableFunc(records, compare)
+	expected := []record{{"d", 10}, {"b", 20}, {"a", 30}, {"c", 30}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %v, but got %v", expected, records)
+	}
+}
@@ -0,0 +1,15 @@
+package function
+
+import (
+	"bytes"
+	"net"
+)
+
+// CompareIP compares the IP addresses a and b, which are normalized to
+// the 16-byte form and compared lexically, so the IPv4 address and
+// the IPv4-in-IPv6 address compare consistently.
+//
+// Return a positive integer if a > b, 0 if a == b, a negative if a < b.
+func CompareIP(a, b net.IP) int {
+	return bytes.Compare(a.To16(), b.To16())
+}
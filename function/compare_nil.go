@@ -0,0 +1,57 @@
+package function
+
+import "reflect"
+
+// NilsLast returns a comparison function, which puts the nil values after
+// the non-nil ones, and delegates to cmp for the non-nil pairs.
+//
+// The value is nil if it is the untyped nil or the nil pointer, interface,
+// map, slice, func or chan. Two nil values are equal.
+func NilsLast(cmp func(a, b interface{}) int) func(a, b interface{}) int {
+	return func(a, b interface{}) int {
+		if r, ok := compareNils(a, b); ok {
+			return -r
+		}
+		return cmp(a, b)
+	}
+}
+
+// NilsFirst is the same as NilsLast, but puts the nil values before
+// the non-nil ones.
+func NilsFirst(cmp func(a, b interface{}) int) func(a, b interface{}) int {
+	return func(a, b interface{}) int {
+		if r, ok := compareNils(a, b); ok {
+			return r
+		}
+		return cmp(a, b)
+	}
+}
+
+// compareNils compares a and b as nil is less than non-nil, and returns false
+// if both of them are not nil.
+func compareNils(a, b interface{}) (int, bool) {
+	switch anil, bnil := isNil(a), isNil(b); {
+	case anil && bnil:
+		return 0, true
+	case anil:
+		return -1, true
+	case bnil:
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice,
+		reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
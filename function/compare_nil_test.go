@@ -0,0 +1,30 @@
+package function
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNilsLast(t *testing.T) {
+	var nilp *int
+	values := []interface{}{3, nil, 1, nilp, 2}
+	cmp := NilsLast(Compare)
+	sort.SliceStable(values, func(i, j int) bool { return cmp(values[i], values[j]) < 0 })
+
+	expected := []interface{}{1, 2, 3, nil, nilp}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, but got %v", expected, values)
+	}
+}
+
+func TestNilsFirst(t *testing.T) {
+	values := []interface{}{"b", nil, "c", nil, "a"}
+	cmp := NilsFirst(Compare)
+	sort.SliceStable(values, func(i, j int) bool { return cmp(values[i], values[j]) < 0 })
+
+	expected := []interface{}{nil, nil, "a", "b", "c"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, but got %v", expected, values)
+	}
+}
@@ -0,0 +1,173 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CompareReflect compares v1 and v2 by the reflection as the best effort,
+// which is the fallback of Compare.
+//
+// It supports bool (false < true), all the kinds of int, uint and float,
+// string, and slice, array, map and struct whose elements or fields are
+// compared recursively:
+//
+//   - slice and array are compared element by element, then by the length.
+//   - map is compared by the entries ordered by the keys, that's, key by key
+//     and value by value, then by the length.
+//   - struct is compared field by field in the order of the definition.
+//   - pointer is compared by the pointed-to values recursively by CompareE,
+//     regardless of the pointer identity. The nil pointer is less than
+//     the non-nil one, and two nil pointers are equal. Notice: the cyclic
+//     pointers are not detected, which will recurse endlessly.
+//
+// The element implementing the interface Comparer is compared by itself.
+//
+// Return an error if the types of v1 and v2 are not identical, or the type
+// is not supported.
+func CompareReflect(v1, v2 interface{}) (int, error) {
+	if v1 == nil || v2 == nil {
+		if v1 == nil && v2 == nil {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("cannot compare %T with %T", v1, v2)
+	}
+
+	return compareValue(reflect.ValueOf(v1), reflect.ValueOf(v2))
+}
+
+func compareValue(v1, v2 reflect.Value) (int, error) {
+	if v1.Type() != v2.Type() {
+		return 0, fmt.Errorf("the types are not identical: %s and %s",
+			v1.Type(), v2.Type())
+	}
+
+	if v1.CanInterface() {
+		if c, ok := v1.Interface().(Comparer); ok {
+			return c.Compare(v2.Interface()), nil
+		}
+	}
+
+	switch v1.Kind() {
+	case reflect.Bool:
+		b1, b2 := v1.Bool(), v2.Bool()
+		if b1 == b2 {
+			return 0, nil
+		} else if b2 {
+			return -1, nil
+		}
+		return 1, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i1, i2 := v1.Int(), v2.Int()
+		if i1 < i2 {
+			return -1, nil
+		} else if i1 > i2 {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		u1, u2 := v1.Uint(), v2.Uint()
+		if u1 < u2 {
+			return -1, nil
+		} else if u1 > u2 {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Float32, reflect.Float64:
+		f1, f2 := v1.Float(), v2.Float()
+		if f1 < f2 {
+			return -1, nil
+		} else if f1 > f2 {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.String:
+		return strings.Compare(v1.String(), v2.String()), nil
+	case reflect.Slice, reflect.Array:
+		return compareSeqValue(v1, v2)
+	case reflect.Map:
+		return compareMapValue(v1, v2)
+	case reflect.Struct:
+		for i, n := 0, v1.NumField(); i < n; i++ {
+			if r, err := compareValue(v1.Field(i), v2.Field(i)); err != nil || r != 0 {
+				return r, err
+			}
+		}
+		return 0, nil
+	case reflect.Ptr:
+		switch nil1, nil2 := v1.IsNil(), v2.IsNil(); {
+		case nil1 && nil2:
+			return 0, nil
+		case nil1:
+			return -1, nil
+		case nil2:
+			return 1, nil
+		}
+		if e1, e2 := v1.Elem(), v2.Elem(); e1.CanInterface() {
+			return CompareE(e1.Interface(), e2.Interface())
+		}
+		return compareValue(v1.Elem(), v2.Elem())
+	case reflect.Interface:
+		if v1.IsNil() || v2.IsNil() {
+			if v1.IsNil() && v2.IsNil() {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("cannot compare the nil interface with non-nil")
+		}
+		return compareValue(v1.Elem(), v2.Elem())
+	default:
+		return 0, fmt.Errorf("the type is not supported: %s", v1.Type())
+	}
+}
+
+func compareSeqValue(v1, v2 reflect.Value) (int, error) {
+	len1, len2 := v1.Len(), v2.Len()
+	_len := Min(len1, len2).(int)
+	for i := 0; i < _len; i++ {
+		if r, err := compareValue(v1.Index(i), v2.Index(i)); err != nil || r != 0 {
+			return r, err
+		}
+	}
+	return compareLen(len1, len2), nil
+}
+
+func compareMapValue(v1, v2 reflect.Value) (int, error) {
+	keys1, err := sortedMapKeys(v1)
+	if err != nil {
+		return 0, err
+	}
+	keys2, err := sortedMapKeys(v2)
+	if err != nil {
+		return 0, err
+	}
+
+	len1, len2 := len(keys1), len(keys2)
+	_len := Min(len1, len2).(int)
+	for i := 0; i < _len; i++ {
+		r, err := compareValue(keys1[i], keys2[i])
+		if err != nil || r != 0 {
+			return r, err
+		}
+
+		r, err = compareValue(v1.MapIndex(keys1[i]), v2.MapIndex(keys2[i]))
+		if err != nil || r != 0 {
+			return r, err
+		}
+	}
+	return compareLen(len1, len2), nil
+}
+
+func sortedMapKeys(m reflect.Value) (keys []reflect.Value, err error) {
+	keys = m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		r, _err := compareValue(keys[i], keys[j])
+		if _err != nil && err == nil {
+			err = _err
+		}
+		return r < 0
+	})
+	return
+}
@@ -1,7 +1,6 @@
 package function
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -15,36 +14,36 @@ func compareLen(len1, len2 int) int {
 	}
 }
 
-func compareSlice(v1, v2 interface{}) int {
+func compareSlice(v1, v2 interface{}) (int, bool) {
 	switch _v1 := v1.(type) {
 	case []int:
-		return compareIntSlice(_v1, v2.([]int))
+		return compareIntSlice(_v1, v2.([]int)), true
 	case []uint:
-		return compareUintSlice(_v1, v2.([]uint))
+		return compareUintSlice(_v1, v2.([]uint)), true
 	case []int8:
-		return compareInt8Slice(_v1, v2.([]int8))
+		return compareInt8Slice(_v1, v2.([]int8)), true
 	case []uint8:
-		return compareUint8Slice(_v1, v2.([]uint8))
+		return compareUint8Slice(_v1, v2.([]uint8)), true
 	case []int16:
-		return compareInt16Slice(_v1, v2.([]int16))
+		return compareInt16Slice(_v1, v2.([]int16)), true
 	case []uint16:
-		return compareUint16Slice(_v1, v2.([]uint16))
+		return compareUint16Slice(_v1, v2.([]uint16)), true
 	case []int32:
-		return compareInt32Slice(_v1, v2.([]int32))
+		return compareInt32Slice(_v1, v2.([]int32)), true
 	case []uint32:
-		return compareUint32Slice(_v1, v2.([]uint32))
+		return compareUint32Slice(_v1, v2.([]uint32)), true
 	case []int64:
-		return compareInt64Slice(_v1, v2.([]int64))
+		return compareInt64Slice(_v1, v2.([]int64)), true
 	case []uint64:
-		return compareUint64Slice(_v1, v2.([]uint64))
+		return compareUint64Slice(_v1, v2.([]uint64)), true
 	case []string:
-		return compareStringSlice(_v1, v2.([]string))
+		return compareStringSlice(_v1, v2.([]string)), true
 	case []float32:
-		return compareFloat32Slice(_v1, v2.([]float32))
+		return compareFloat32Slice(_v1, v2.([]float32)), true
 	case []float64:
-		return compareFloat64Slice(_v1, v2.([]float64))
+		return compareFloat64Slice(_v1, v2.([]float64)), true
 	default:
-		panic(fmt.Sprintf("Type is not supported: %t\n", _v1))
+		return 0, false
 	}
 }
 
@@ -228,3 +227,28 @@ func compareStringSlice(v1, v2 []string) int {
 
 	return compareLen(len1, len2)
 }
+
+// FirstDiff returns the index of the first element where Compare(a[i], b[i])
+// is not 0, or the length of the shorter one if it's the prefix of the other,
+// or -1 if a and b are equal.
+//
+// If a or b is not a slice or array type, or the elements cannot be compared,
+// it will panic.
+func FirstDiff(a, b interface{}) int {
+	va, vb := interfaces(a), interfaces(b)
+	_len := len(va)
+	if len(vb) < _len {
+		_len = len(vb)
+	}
+
+	for i := 0; i < _len; i++ {
+		if Compare(va[i], vb[i]) != 0 {
+			return i
+		}
+	}
+
+	if len(va) == len(vb) {
+		return -1
+	}
+	return _len
+}
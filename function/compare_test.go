@@ -1,7 +1,10 @@
 package function
 
 import (
+	"math"
+	"net"
 	"testing"
+	"time"
 )
 
 func TestCompare(t *testing.T) {
@@ -27,3 +30,203 @@ func TestCompare(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestCompareReflect(t *testing.T) {
+	type inner struct {
+		Name string
+		Tags []string
+	}
+	type outer struct {
+		ID    int
+		Ratio float32
+		Valid bool
+		Inner inner
+	}
+
+	cases := []struct {
+		v1, v2 interface{}
+		result int
+	}{
+		{false, true, -1},
+		{true, true, 0},
+		{int16(-1), int16(1), -1},
+		{uintptr(2), uintptr(1), 1},
+		{float32(1.5), float32(1.5), 0},
+		{"abc", "abd", -1},
+		{[2]int{1, 2}, [2]int{1, 3}, -1},
+		{[]bool{true}, []bool{true, false}, -1},
+		{map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1, "b": 2}, 0},
+		{map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1, "c": 0}, -1},
+		{map[string]int{"a": 1, "b": 3}, map[string]int{"a": 1, "b": 2}, 1},
+		{[]interface{}{1, "a"}, []interface{}{1, "b"}, -1},
+		{
+			outer{ID: 1, Inner: inner{Name: "a", Tags: []string{"x"}}},
+			outer{ID: 1, Inner: inner{Name: "a", Tags: []string{"y"}}},
+			-1,
+		},
+		{
+			outer{ID: 1, Ratio: 0.5, Valid: true, Inner: inner{Name: "a"}},
+			outer{ID: 1, Ratio: 0.5, Valid: true, Inner: inner{Name: "a"}},
+			0,
+		},
+		{outer{ID: 2}, outer{ID: 1, Ratio: 1}, 1},
+	}
+
+	for i, c := range cases {
+		if r, err := CompareReflect(c.v1, c.v2); err != nil {
+			t.Errorf("%d: unexpected error: %s", i, err)
+		} else if r != c.result {
+			t.Errorf("%d: expected %d, but got %d", i, c.result, r)
+		}
+
+		if r := Compare(c.v1, c.v2); r != c.result {
+			t.Errorf("%d: Compare expected %d, but got %d", i, c.result, r)
+		}
+	}
+}
+
+func TestCompareE(t *testing.T) {
+	if _, err := CompareE(1, int64(1)); err == nil {
+		t.Error("expected an error for the different types")
+	}
+
+	if _, err := CompareE(make(chan int), make(chan int)); err == nil {
+		t.Error("expected an error for the unsupported type")
+	}
+
+	if r, err := CompareE([]int{1, 2}, []int{1}); err != nil || r != 1 {
+		t.Errorf("expected 1, but got %d, %v", r, err)
+	}
+}
+
+func TestComparePointer(t *testing.T) {
+	i1, i2, i3 := 1, 1, 2
+	s1, s2 := "a", "b"
+	var nilp *int
+
+	cases := []struct {
+		v1, v2 interface{}
+		result int
+	}{
+		{&i1, &i2, 0},
+		{&i1, &i3, -1},
+		{&i3, &i1, 1},
+		{&s1, &s2, -1},
+		{nilp, &i1, -1},
+		{&i1, nilp, 1},
+		{nilp, (*int)(nil), 0},
+		{[]*int{&i1, &i3}, []*int{&i2, &i3}, 0},
+		{struct{ P *string }{&s2}, struct{ P *string }{&s1}, 1},
+	}
+
+	for i, c := range cases {
+		if r := Compare(c.v1, c.v2); r != c.result {
+			t.Errorf("%d: expected %d, but got %d", i, c.result, r)
+		}
+	}
+}
+
+func TestDurationEqualWithin(t *testing.T) {
+	tol := 10 * time.Millisecond
+	if !DurationEqualWithin(time.Second, time.Second+5*time.Millisecond, tol) {
+		t.Error("expected the durations within the tolerance")
+	}
+	if !DurationEqualWithin(time.Second+10*time.Millisecond, time.Second, tol) {
+		t.Error("expected the durations on the boundary of the tolerance")
+	}
+	if DurationEqualWithin(time.Second, time.Second+11*time.Millisecond, tol) {
+		t.Error("expected the durations outside the tolerance")
+	}
+	if DurationEqualWithin(-time.Second, time.Second, tol) {
+		t.Error("expected the durations outside the tolerance")
+	}
+	if !DurationEqualWithin(-time.Second, -time.Second-time.Millisecond, tol) {
+		t.Error("expected the negative durations within the tolerance")
+	}
+	if DurationEqualWithin(math.MinInt64, math.MaxInt64, tol) {
+		t.Error("expected the overflowed difference outside the tolerance")
+	}
+	if DurationEqualWithin(math.MaxInt64, -1, math.MaxInt64) {
+		t.Error("expected the difference greater than math.MaxInt64 outside the tolerance")
+	}
+	if !DurationEqualWithin(math.MaxInt64, 0, math.MaxInt64) {
+		t.Error("expected the durations on the boundary of the max tolerance")
+	}
+	if DurationEqualWithin(time.Second, time.Second, -1) {
+		t.Error("expected the negative tolerance to be never satisfied")
+	}
+}
+
+func TestCompareTimeDuration(t *testing.T) {
+	cases := []struct {
+		a, b   time.Duration
+		result int
+	}{
+		{5 * time.Second, 5000 * time.Millisecond, 0},
+		{5 * time.Second, 5001 * time.Millisecond, -1},
+		{time.Minute, 59 * time.Second, 1},
+		{-time.Second, 0, -1},
+	}
+
+	for _, c := range cases {
+		if r := CompareTimeDuration(c.a, c.b); r != c.result {
+			t.Errorf("CompareTimeDuration(%s, %s): expected %d, but got %d", c.a, c.b, c.result, r)
+		}
+		if r := Compare(c.a, c.b); r != c.result {
+			t.Errorf("Compare(%s, %s): expected %d, but got %d", c.a, c.b, c.result, r)
+		}
+	}
+
+	if !EQ(5*time.Second, 5000*time.Millisecond) || !LT(time.Millisecond, time.Second) {
+		t.Error("unexpected the comparison of the durations")
+	}
+}
+
+func TestCompareIP(t *testing.T) {
+	cases := []struct {
+		a, b   string
+		result int
+	}{
+		{"10.0.0.1", "10.0.0.2", -1},
+		{"10.0.0.10", "9.0.0.1", 1},
+		{"::1", "::2", -1},
+		{"fe80::1", "2001:db8::1", 1},
+		{"192.168.1.1", "::ffff:192.168.1.1", 0},
+		{"::ffff:10.0.0.1", "10.0.0.2", -1},
+	}
+
+	for _, c := range cases {
+		a, b := net.ParseIP(c.a), net.ParseIP(c.b)
+		if r := CompareIP(a, b); r != c.result {
+			t.Errorf("CompareIP(%s, %s): expected %d, but got %d", c.a, c.b, c.result, r)
+		}
+		if r := Compare(a, b); r != c.result {
+			t.Errorf("Compare(%s, %s): expected %d, but got %d", c.a, c.b, c.result, r)
+		}
+	}
+
+	if r := Compare(net.ParseIP("1.2.3.4").To4(), net.ParseIP("1.2.3.4")); r != 0 {
+		t.Errorf("expected the 4-byte and 16-byte forms to be equal, but got %d", r)
+	}
+}
+
+func TestFirstDiff(t *testing.T) {
+	cases := []struct {
+		a, b   interface{}
+		result int
+	}{
+		{[]int{1, 2, 3}, []int{1, 2, 3}, -1},
+		{[]int{}, []int(nil), -1},
+		{[]int{1, 2}, []int{1, 2, 3}, 2},
+		{[]string{"a", "b", "c"}, []string{"a"}, 1},
+		{[]int{1, 2, 3}, []int{1, 5, 3}, 1},
+		{[]interface{}{1.5, "x"}, []interface{}{1.5, "y"}, 1},
+		{[2]int{0, 1}, [2]int{1, 1}, 0},
+	}
+
+	for i, c := range cases {
+		if r := FirstDiff(c.a, c.b); r != c.result {
+			t.Errorf("%d: expected %d, but got %d", i, c.result, r)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package function
+
+import "time"
+
+// DurationEqualWithin returns true if the difference between the durations
+// a and b is not greater than tol, or returns false, such as tol is negative.
+//
+// The difference is computed as uint64, so it doesn't overflow even if
+// the durations are far apart, such as math.MinInt64 and math.MaxInt64.
+func DurationEqualWithin(a, b, tol time.Duration) bool {
+	if tol < 0 {
+		return false
+	} else if a < b {
+		a, b = b, a
+	}
+	return uint64(a)-uint64(b) <= uint64(tol)
+}
+
+// CompareTimeDuration compares the durations a and b, which returns -1 if a is
+// less than b, 1 if a is greater than b, or 0 if they are equal.
+func CompareTimeDuration(a, b time.Duration) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
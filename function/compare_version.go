@@ -0,0 +1,146 @@
+package function
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type version struct {
+	core       [3]uint64
+	prerelease []string
+}
+
+// parseVersion parses the semver-ish version, such as "v1.2.3-rc.1+build.5".
+func parseVersion(s string) (v version, err error) {
+	orig := s
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i > -1 {
+		if !isVersionIdents(s[i+1:]) {
+			return v, fmt.Errorf("invalid build metadata of the version '%s'", orig)
+		}
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i > -1 {
+		if !isVersionIdents(s[i+1:]) {
+			return v, fmt.Errorf("invalid pre-release of the version '%s'", orig)
+		}
+		v.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+
+	nums := strings.Split(s, ".")
+	if len(nums) > 3 {
+		return v, fmt.Errorf("invalid version '%s'", orig)
+	}
+	for i, num := range nums {
+		if !isDigits(num) {
+			return v, fmt.Errorf("invalid version '%s'", orig)
+		}
+		if v.core[i], err = strconv.ParseUint(num, 10, 64); err != nil {
+			return v, fmt.Errorf("invalid version '%s': %w", orig, err)
+		}
+	}
+	return
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isVersionIdents reports whether s is the dot-separated non-empty
+// identifiers consisting of [0-9A-Za-z-].
+func isVersionIdents(s string) bool {
+	for _, ident := range strings.Split(s, ".") {
+		if ident == "" {
+			return false
+		}
+		for _, c := range ident {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CompareVersions compares the semver-ish versions a and b by the precedence
+// of the semantic versioning, and returns -1 if a is less than b, 1 if a is
+// greater than b, or 0 if they are equal.
+//
+// The version is "major.minor.patch", which may have the leading "v",
+// the pre-release like "-rc.1" and the build metadata like "+build.5".
+// The missing minor or patch is 0, such as "1.2" is equal to "1.2.0".
+// The rules are:
+//
+//   - major, minor and patch are compared numerically in turn.
+//   - the version with the pre-release is less than the release,
+//     such as "1.0.0-rc.1" < "1.0.0".
+//   - the pre-release identifiers are compared one by one, that's,
+//     the numeric ones numerically, the others in the ASCII order,
+//     and the numeric one is less than the others. If all the identifiers
+//     are equal, the shorter pre-release is less.
+//   - the build metadata is ignored.
+//
+// Return an error if a or b is not a valid version.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.core {
+		if va.core[i] < vb.core[i] {
+			return -1, nil
+		} else if va.core[i] > vb.core[i] {
+			return 1, nil
+		}
+	}
+
+	switch pa, pb := len(va.prerelease), len(vb.prerelease); {
+	case pa == 0 && pb == 0:
+		return 0, nil
+	case pa == 0:
+		return 1, nil
+	case pb == 0:
+		return -1, nil
+	}
+
+	for i := 0; i < len(va.prerelease) && i < len(vb.prerelease); i++ {
+		if r := comparePrerelease(va.prerelease[i], vb.prerelease[i]); r != 0 {
+			return r, nil
+		}
+	}
+	return compareLen(len(va.prerelease), len(vb.prerelease)), nil
+}
+
+func comparePrerelease(a, b string) int {
+	na, nb := isDigits(a), isDigits(b)
+	switch {
+	case na && nb:
+		// Compare the numbers as the strings to avoid the overflow.
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if r := compareLen(len(a), len(b)); r != 0 {
+			return r
+		}
+		return strings.Compare(a, b)
+	case na:
+		return -1
+	case nb:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
@@ -0,0 +1,55 @@
+package function
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	// In the ascending order by the semver precedence.
+	versions := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.2",
+		"v1.2.1",
+		"1.10.0",
+		"2.0.0",
+	}
+
+	for i, a := range versions {
+		for j, b := range versions {
+			r, err := CompareVersions(a, b)
+			if err != nil {
+				t.Fatal(err)
+			} else if expected := compareLen(i, j); r != expected {
+				t.Errorf("%s <=> %s: expected %d, but got %d", a, b, expected, r)
+			}
+		}
+	}
+
+	if r, _ := CompareVersions("1.0.0+build.1", "v1.0.0+build.2"); r != 0 {
+		t.Errorf("expected the build metadata to be ignored, but got %d", r)
+	}
+
+	tags := []string{"v2.0.0", "v1.0.0", "v1.0.0-rc.1", "v1.10.0", "v1.9.0"}
+	sort.Slice(tags, func(i, j int) bool {
+		r, _ := CompareVersions(tags[i], tags[j])
+		return r < 0
+	})
+	if tags[0] != "v1.0.0-rc.1" || tags[2] != "v1.9.0" || tags[4] != "v2.0.0" {
+		t.Errorf("unexpected the sorted tags: %v", tags)
+	}
+
+	for _, invalid := range []string{"", "1.2.3.4", "1.x.0", "1.0.0-", "1.0.0-rc..1", "1.0.0+", "1.0.0-rc_1", "-1.0"} {
+		if _, err := CompareVersions(invalid, "1.0.0"); err == nil {
+			t.Errorf("expected an error for the invalid version '%s'", invalid)
+		}
+	}
+}
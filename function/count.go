@@ -0,0 +1,27 @@
+package function
+
+// Count returns the number of the elements in the slice equal to value,
+// which are compared by Compare. The elements not comparable with value,
+// such as the different types, are considered unequal.
+//
+// If slice is not a slice or array type, it will panic.
+func Count(slice interface{}, value interface{}) (n int) {
+	for _, v := range interfaces(slice) {
+		if r, err := CompareE(v, value); err == nil && r == 0 {
+			n++
+		}
+	}
+	return
+}
+
+// CountBy returns the number of the elements in the slice matching pred.
+//
+// If slice is not a slice or array type, it will panic.
+func CountBy(slice interface{}, pred func(interface{}) bool) (n int) {
+	for _, v := range interfaces(slice) {
+		if pred(v) {
+			n++
+		}
+	}
+	return
+}
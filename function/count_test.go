@@ -0,0 +1,28 @@
+package function
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleCount() {
+	levels := []string{"INFO", "ERROR", "INFO", "DEBUG", "INFO"}
+	fmt.Println(Count(levels, "INFO"))
+	fmt.Println(Count([]interface{}{1, "1", 1}, 1))
+
+	// Output:
+	// 3
+	// 2
+}
+
+func ExampleCountBy() {
+	statuses := []int{200, 404, 500, 201, 503}
+	fmt.Println(CountBy(statuses, func(v interface{}) bool { return v.(int) >= 500 }))
+	fmt.Println(CountBy([]string{"a.log", "b.txt"}, func(v interface{}) bool {
+		return strings.HasSuffix(v.(string), ".log")
+	}))
+
+	// Output:
+	// 2
+	// 1
+}
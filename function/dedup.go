@@ -0,0 +1,29 @@
+package function
+
+// DedupByKeeping removes the duplicate elements of the slice sharing the same
+// key extracted by keyfn, and keeps the one selected by the reducer keep,
+// such as the one with the latest timestamp or the largest value.
+//
+// keep receives the kept element so far and the current one with the same key,
+// and returns the one to keep. The result is in the order of the first-seen
+// keys.
+//
+// The key must be hashable. If slice is not a slice or array type,
+// it will panic.
+func DedupByKeeping(slice interface{}, keyfn func(interface{}) interface{},
+	keep func(a, b interface{}) interface{}) []interface{} {
+
+	values := interfaces(slice)
+	indexes := make(map[interface{}]int, len(values))
+	results := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		key := keyfn(v)
+		if i, ok := indexes[key]; ok {
+			results[i] = keep(results[i], v)
+		} else {
+			indexes[key] = len(results)
+			results = append(results, v)
+		}
+	}
+	return results
+}
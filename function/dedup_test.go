@@ -0,0 +1,26 @@
+package function
+
+import (
+	"fmt"
+)
+
+func ExampleDedupByKeeping() {
+	type record struct {
+		Name  string
+		Value int
+	}
+
+	records := []record{{"a", 1}, {"b", 5}, {"a", 3}, {"c", 2}, {"b", 4}}
+	results := DedupByKeeping(records,
+		func(v interface{}) interface{} { return v.(record).Name },
+		func(a, b interface{}) interface{} {
+			if b.(record).Value > a.(record).Value {
+				return b
+			}
+			return a
+		})
+	fmt.Println(results)
+
+	// Output:
+	// [{a 3} {b 5} {c 2}]
+}
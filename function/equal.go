@@ -0,0 +1,74 @@
+package function
+
+import (
+	"math"
+	"reflect"
+	"sort"
+)
+
+// EqualUnordered returns true if the slices a and b contain the same elements
+// with the same multiplicities regardless of the order, which are compared
+// by Compare. Or return false.
+//
+// The inputs are not modified, because the sorted copies are compared.
+//
+// If a or b is not a slice or array type, it will panic.
+func EqualUnordered(a, b interface{}) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+
+	_a, _b := sortedCopy(a), sortedCopy(b)
+	if len(_a) != len(_b) {
+		return false
+	}
+
+	for i := range _a {
+		if Compare(_a[i], _b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedCopy(slice interface{}) []interface{} {
+	values := append([]interface{}(nil), interfaces(slice)...)
+	sort.SliceStable(values, func(i, j int) bool { return LT(values[i], values[j]) })
+	return values
+}
+
+// EqualFloat returns true if the difference between the floats a and b is not
+// greater than the absolute tolerance epsilon, or returns false.
+//
+// The absolute tolerance suits the values of the known scale, especially
+// near zero, such as the ratios in [0, 1]. For the values of the arbitrary
+// magnitude, use EqualFloatRel instead.
+//
+// NaN is not equal to any value including itself, and the infinities
+// are equal only to the same infinity.
+func EqualFloat(a, b, epsilon float64) bool {
+	if a == b {
+		return true
+	} else if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return false
+	}
+	return math.Abs(a-b) <= epsilon
+}
+
+// EqualFloatRel returns true if the difference between the floats a and b
+// is not greater than the relative tolerance relTol of the larger magnitude
+// of them, such as 1e-9, or returns false.
+//
+// The relative tolerance suits the values of the arbitrary magnitude, such as
+// the aggregated metrics. But no value except zero is relatively close to zero,
+// so use EqualFloat for the values expected to be near zero.
+//
+// NaN and the infinities are handled as EqualFloat.
+func EqualFloatRel(a, b, relTol float64) bool {
+	if a == b {
+		return true
+	} else if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return false
+	}
+	return math.Abs(a-b) <= relTol*math.Max(math.Abs(a), math.Abs(b))
+}
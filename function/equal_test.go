@@ -0,0 +1,61 @@
+package function
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEqualUnordered(t *testing.T) {
+	a := []int{3, 1, 2, 1}
+	if !EqualUnordered(a, []int{1, 1, 2, 3}) {
+		t.Error("expected the equal slices")
+	}
+	if a[0] != 3 || a[3] != 1 {
+		t.Errorf("the input has been modified: %v", a)
+	}
+
+	if EqualUnordered([]int{1, 1, 2}, []int{1, 2, 2}) {
+		t.Error("expected the different multiplicities to be unequal")
+	}
+	if EqualUnordered([]int{1, 2}, []int{1, 2, 2}) {
+		t.Error("expected the different lengths to be unequal")
+	}
+	if EqualUnordered([]int{1, 2}, []int64{1, 2}) {
+		t.Error("expected the different types to be unequal")
+	}
+	if !EqualUnordered([]interface{}{"b", "a"}, []interface{}{"a", "b"}) {
+		t.Error("expected the equal slices")
+	}
+	if !EqualUnordered([]string{}, []string(nil)) {
+		t.Error("expected the empty slices to be equal")
+	}
+}
+
+func TestEqualFloat(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		a, b, tol float64
+		abs, rel  bool
+	}{
+		{0.1 + 0.2, 0.3, 1e-9, true, true},
+		{1e9, 1e9 + 1, 1e-6, false, true},
+		{1e-12, 0, 1e-9, true, false},
+		{0, 0, 0, true, true},
+		{0, math.Copysign(0, -1), 0, true, true},
+		{1, 1.1, 1e-3, false, false},
+		{nan, nan, 1, false, false},
+		{nan, 1, 1, false, false},
+		{inf, inf, 1e-9, true, true},
+		{inf, -inf, 1e-9, false, false},
+		{inf, 1e308, 1, false, false},
+	}
+
+	for i, test := range tests {
+		if r := EqualFloat(test.a, test.b, test.tol); r != test.abs {
+			t.Errorf("%d: expected the absolute %v, but got %v", i, test.abs, r)
+		}
+		if r := EqualFloatRel(test.a, test.b, test.tol); r != test.rel {
+			t.Errorf("%d: expected the relative %v, but got %v", i, test.rel, r)
+		}
+	}
+}
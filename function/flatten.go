@@ -0,0 +1,54 @@
+package function
+
+import "reflect"
+
+// Flatten concatenates the inner slices of s in order, and the nil or empty
+// inner slices are skipped. It always returns a non-nil slice.
+func Flatten[T any](s [][]T) []T {
+	var _len int
+	for _, inner := range s {
+		_len += len(inner)
+	}
+
+	result := make([]T, 0, _len)
+	for _, inner := range s {
+		result = append(result, inner...)
+	}
+	return result
+}
+
+// FlattenDepth flattens the nested slices or arrays in slice by depth levels,
+// and returns the elements in order. If depth is 0, the elements of slice
+// are returned as they are, and if depth is negative, slice is flattened fully.
+//
+// For the mixed-depth input, the non-slice elements are kept where they are
+// at any level, and the nested slices deeper than depth are kept as the
+// elements. For example,
+//
+//	FlattenDepth([]interface{}{1, []interface{}{2, []int{3, 4}}}, 1)
+//	// => [1 2 [3 4]]
+//
+// If slice is not a slice or array type, it will panic.
+func FlattenDepth(slice interface{}, depth int) []interface{} {
+	return flattenDepth(make([]interface{}, 0, 8), reflect.ValueOf(slice), depth)
+}
+
+func flattenDepth(result []interface{}, v reflect.Value, depth int) []interface{} {
+	if kind := v.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		panic(ErrNotSliceOrArray)
+	}
+
+	for i, _len := 0, v.Len(); i < _len; i++ {
+		elem := v.Index(i)
+		for elem.Kind() == reflect.Interface && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+
+		if kind := elem.Kind(); depth != 0 && (kind == reflect.Slice || kind == reflect.Array) {
+			result = flattenDepth(result, elem, depth-1)
+		} else {
+			result = append(result, v.Index(i).Interface())
+		}
+	}
+	return result
+}
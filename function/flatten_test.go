@@ -0,0 +1,44 @@
+package function
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	s := [][]int{{1, 2}, nil, {}, {3}, {4, 5, 6}, nil}
+	expected := []int{1, 2, 3, 4, 5, 6}
+	if result := Flatten(s); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, but got %v", expected, result)
+	}
+
+	if result := Flatten([][]string{nil, {}}); result == nil || len(result) != 0 {
+		t.Errorf("expected an empty slice, but got %#v", result)
+	}
+	if result := Flatten[string](nil); result == nil || len(result) != 0 {
+		t.Errorf("expected an empty slice, but got %#v", result)
+	}
+}
+
+func TestFlattenDepth(t *testing.T) {
+	nested := []interface{}{1, []interface{}{2, []int{3, 4}}, [2]string{"a", "b"}, []int{}}
+	tests := []struct {
+		depth    int
+		expected []interface{}
+	}{
+		{0, nested},
+		{1, []interface{}{1, 2, []int{3, 4}, "a", "b"}},
+		{2, []interface{}{1, 2, 3, 4, "a", "b"}},
+		{-1, []interface{}{1, 2, 3, 4, "a", "b"}},
+	}
+
+	for _, test := range tests {
+		if result := FlattenDepth(nested, test.depth); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("depth %d: expected %v, but got %v", test.depth, test.expected, result)
+		}
+	}
+
+	if result := FlattenDepth([][]int{{1}, {2, 3}}, -1); !reflect.DeepEqual(result, []interface{}{1, 2, 3}) {
+		t.Errorf("expected %v, but got %v", []interface{}{1, 2, 3}, result)
+	}
+}
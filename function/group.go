@@ -0,0 +1,33 @@
+package function
+
+// Group is a run of the consecutive equal elements, see GroupConsecutive.
+type Group struct {
+	Value interface{}
+	Count int
+}
+
+// GroupConsecutive groups the consecutive equal elements of the slice,
+// which are compared by Compare, that's, the run-length encoding.
+// Each Group holds the first element of the run and the length of the run.
+//
+// So the elements are grouped by the value if the slice is sorted.
+//
+// If slice is not a slice or array type, or the elements cannot be compared
+// by Compare, it will panic.
+func GroupConsecutive(slice interface{}) []Group {
+	values := interfaces(slice)
+	if len(values) == 0 {
+		return nil
+	}
+
+	groups := make([]Group, 0, 8)
+	groups = append(groups, Group{Value: values[0], Count: 1})
+	for _, v := range values[1:] {
+		if last := &groups[len(groups)-1]; Compare(last.Value, v) == 0 {
+			last.Count++
+		} else {
+			groups = append(groups, Group{Value: v, Count: 1})
+		}
+	}
+	return groups
+}
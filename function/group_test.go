@@ -0,0 +1,36 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleGroupConsecutive() {
+	levels := []string{"debug", "debug", "info", "info", "info", "warn", "debug"}
+	for _, group := range GroupConsecutive(levels) {
+		fmt.Println(group.Value, group.Count)
+	}
+
+	// Output:
+	// debug 2
+	// info 3
+	// warn 1
+	// debug 1
+}
+
+func TestGroupConsecutive(t *testing.T) {
+	expected := []Group{{1, 3}, {2, 1}, {3, 4}}
+	if groups := GroupConsecutive([]int{1, 1, 1, 2, 3, 3, 3, 3}); !reflect.DeepEqual(groups, expected) {
+		t.Errorf("expected %v, but got %v", expected, groups)
+	}
+
+	expected = []Group{{1, 1}, {2, 1}, {3, 1}}
+	if groups := GroupConsecutive([]int{1, 2, 3}); !reflect.DeepEqual(groups, expected) {
+		t.Errorf("expected %v, but got %v", expected, groups)
+	}
+
+	if groups := GroupConsecutive([]int{}); groups != nil {
+		t.Errorf("expected nil, but got %v", groups)
+	}
+}
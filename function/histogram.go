@@ -0,0 +1,42 @@
+package function
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Histogram counts the numbers of the slice or array in the buckets defined
+// by the edges, which are the half-open intervals [edges[i-1], edges[i]).
+//
+// The result has len(edges)+1 counts, that's, the first is the underflow
+// count of the numbers less than edges[0], the last is the overflow count
+// of the numbers greater than or equal to the last edge, and the ith count
+// between them is of the bucket [edges[i-1], edges[i]). NaN is not counted.
+//
+// The elements may be any kind of int, uint or float, which are converted
+// to float64.
+//
+// If slice is not a slice or array type, any element is not a number,
+// or the edges are empty or not strictly increasing, it will panic.
+func Histogram(slice interface{}, edges []float64) []int {
+	if len(edges) == 0 {
+		panic(fmt.Errorf("the histogram edges must not be empty"))
+	}
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i-1] < edges[i]) {
+			panic(fmt.Errorf("the histogram edges are not strictly increasing: %v", edges))
+		}
+	}
+
+	counts := make([]int, len(edges)+1)
+	for _, v := range interfaces(slice) {
+		f := mustFloat64(v)
+		if f != f { // NaN
+			continue
+		}
+
+		// The index of the first edge greater than f is the bucket.
+		counts[sort.Search(len(edges), func(i int) bool { return edges[i] > f })]++
+	}
+	return counts
+}
@@ -0,0 +1,32 @@
+package function
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleHistogram() {
+	latencies := []float64{0.5, 1, 3, 7, 12, 15, 99, math.NaN()}
+	fmt.Println(Histogram(latencies, []float64{1, 5, 10, 50}))
+	fmt.Println(Histogram([]int{-1, 0, 1}, []float64{0}))
+	fmt.Println(Histogram([]int{}, []float64{0, 1}))
+
+	// Output:
+	// [1 2 1 2 1]
+	// [1 2]
+	// [0 0 0]
+}
+
+func TestHistogramInvalidEdges(t *testing.T) {
+	for _, edges := range [][]float64{nil, {1, 1}, {2, 1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for the edges %v", edges)
+				}
+			}()
+			Histogram([]int{1}, edges)
+		}()
+	}
+}
@@ -0,0 +1,35 @@
+package function
+
+// InInterval returns true if x is in the interval between low and high,
+// whose endpoints are included or excluded by lowInclusive and highInclusive.
+//
+// For example, InInterval(x, low, high, true, false) checks x in [low, high).
+func InInterval(x, low, high interface{}, lowInclusive, highInclusive bool) bool {
+	if lowInclusive {
+		if LT(x, low) {
+			return false
+		}
+	} else if LE(x, low) {
+		return false
+	}
+
+	if highInclusive {
+		return LE(x, high)
+	}
+	return LT(x, high)
+}
+
+// Overlaps returns true if the intervals [aLow, aHigh] and [bLow, bHigh]
+// intersect, whose endpoints are inclusive. Or return false.
+//
+// All the values must be the same comparable type for Compare, and
+// the reversed bounds, that's, low > high, are swapped.
+func Overlaps(aLow, aHigh, bLow, bHigh interface{}) bool {
+	if GT(aLow, aHigh) {
+		aLow, aHigh = aHigh, aLow
+	}
+	if GT(bLow, bHigh) {
+		bLow, bHigh = bHigh, bLow
+	}
+	return LE(aLow, bHigh) && GE(aHigh, bLow)
+}
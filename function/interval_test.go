@@ -0,0 +1,58 @@
+package function
+
+import "testing"
+
+func TestInInterval(t *testing.T) {
+	cases := []struct {
+		x                           int
+		lowInclusive, highInclusive bool
+		result                      bool
+	}{
+		{1, true, true, true},
+		{3, true, true, true},
+		{1, true, false, true},
+		{3, true, false, false},
+		{1, false, true, false},
+		{3, false, true, true},
+		{1, false, false, false},
+		{3, false, false, false},
+		{2, false, false, true},
+		{0, true, true, false},
+		{4, true, true, false},
+	}
+
+	for _, c := range cases {
+		if r := InInterval(c.x, 1, 3, c.lowInclusive, c.highInclusive); r != c.result {
+			t.Errorf("InInterval(%d, 1, 3, %v, %v): expected %v, but got %v",
+				c.x, c.lowInclusive, c.highInclusive, c.result, r)
+		}
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	cases := []struct {
+		aLow, aHigh, bLow, bHigh int
+		result                   bool
+	}{
+		{1, 3, 3, 5, true},  // touching endpoints
+		{3, 5, 1, 3, true},  // touching endpoints
+		{1, 10, 3, 5, true}, // contained
+		{3, 5, 1, 10, true}, // contained
+		{1, 3, 2, 5, true},  // partially overlapping
+		{1, 2, 3, 5, false}, // disjoint
+		{6, 8, 3, 5, false}, // disjoint
+		{3, 1, 5, 2, true},  // reversed bounds
+		{2, 1, 5, 3, false}, // reversed bounds
+	}
+
+	for _, c := range cases {
+		if r := Overlaps(c.aLow, c.aHigh, c.bLow, c.bHigh); r != c.result {
+			t.Errorf("Overlaps(%d, %d, %d, %d): expected %v, but got %v",
+				c.aLow, c.aHigh, c.bLow, c.bHigh, c.result, r)
+		}
+	}
+
+	if !Overlaps("a", "c", "b", "d") {
+		t.Error("expected the string intervals to overlap")
+	}
+}
@@ -0,0 +1,43 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// JoinNonEmpty is the same as strings.Join, but only joins the non-empty
+// parts, so there are no leading, trailing or duplicate separators.
+func JoinNonEmpty(sep string, parts ...string) string {
+	nonempty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonempty = append(nonempty, part)
+		}
+	}
+	return strings.Join(nonempty, sep)
+}
+
+// JoinSlice converts each element of the slice to a string by fmt,
+// then joins them by sep.
+//
+// If slice is not a slice or array type, it will panic.
+func JoinSlice(slice interface{}, sep string) string {
+	switch s := slice.(type) {
+	case []string:
+		return strings.Join(s, sep)
+	case nil:
+		return ""
+	}
+
+	v := reflect.ValueOf(slice)
+	if kind := v.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		panic(ErrNotSliceOrArray)
+	}
+
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
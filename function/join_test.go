@@ -0,0 +1,23 @@
+package function
+
+import (
+	"fmt"
+)
+
+func ExampleJoinNonEmpty() {
+	fmt.Println(JoinNonEmpty("/", "", "var", "", "log", ""))
+	fmt.Println(JoinNonEmpty("/") == "")
+
+	// Output:
+	// var/log
+	// true
+}
+
+func ExampleJoinSlice() {
+	fmt.Println(JoinSlice([]int{1, 2, 3}, ","))
+	fmt.Println(JoinSlice([2]interface{}{"a", 1.5}, " "))
+
+	// Output:
+	// 1,2,3
+	// a 1.5
+}
@@ -1,5 +1,12 @@
 package function
 
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
 // InMap returns true if the key exists.
 func InMap(m map[string]interface{}, key string) bool {
 	if _, ok := m[key]; ok {
@@ -15,3 +22,56 @@ func InMapString(m map[string]string, key string) bool {
 	}
 	return false
 }
+
+// Keys returns the keys of the map m in an indeterminate order.
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns the values of the map m in an indeterminate order.
+func Values[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// SortedKeys returns the keys of the map m in the ascending order.
+//
+// Notice: it uses cmp.Ordered in the standard library instead of
+// golang.org/x/exp/constraints, so as not to depend on the third-part package.
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := Keys(m)
+	sort.Slice(keys, func(i, j int) bool { return cmp.Less(keys[i], keys[j]) })
+	return keys
+}
+
+// Entry is a key-value entry of the map.
+type Entry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// SortedEntries returns the entries of the map m sorted by the keys,
+// which are compared by Compare, so the order is deterministic, such as
+// producing the stable diffs.
+//
+// If m is not a map, or the keys cannot be compared, it will panic.
+func SortedEntries(m interface{}) []Entry {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		panic(fmt.Errorf("the value is not a map: %T", m))
+	}
+
+	entries := make([]Entry, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		entries = append(entries, Entry{Key: iter.Key().Interface(), Value: iter.Value().Interface()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return LT(entries[i].Key, entries[j].Key) })
+	return entries
+}
@@ -0,0 +1,54 @@
+package function
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestKeysValues(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+
+	values := Values(m)
+	sort.Ints(values)
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	m := map[int]string{5: "e", 1: "a", 3: "c", -2: "z", 4: "d"}
+	for i := 0; i < 10; i++ {
+		if keys := SortedKeys(m); !reflect.DeepEqual(keys, []int{-2, 1, 3, 4, 5}) {
+			t.Fatalf("expected the ascending keys, but got %v", keys)
+		}
+	}
+
+	if keys := SortedKeys(map[string]bool{}); len(keys) != 0 {
+		t.Errorf("expected no keys, but got %v", keys)
+	}
+}
+
+func TestSortedEntries(t *testing.T) {
+	entries := SortedEntries(map[string]int{"b": 2, "c": 3, "a": 1})
+	expected := []Entry{{"a", 1}, {"b", 2}, {"c", 3}}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected %v, but got %v", expected, entries)
+	}
+
+	entries = SortedEntries(map[int]string{10: "x", -1: "y", 3: "z"})
+	expected = []Entry{{-1, "y"}, {3, "z"}, {10, "x"}}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected %v, but got %v", expected, entries)
+	}
+
+	if entries = SortedEntries(map[int]int(nil)); len(entries) != 0 {
+		t.Errorf("expected no entries, but got %v", entries)
+	}
+}
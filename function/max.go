@@ -73,3 +73,21 @@ func MaxInSlice(v interface{}) (max interface{}) {
 		panic(errType)
 	}
 }
+
+// ArgMax returns the index of the maximal element in the slice s by less.
+//
+// Return the first index if there are more than one maximal elements,
+// or -1 if s is empty.
+func ArgMax[T any](s []T, less func(a, b T) bool) int {
+	if len(s) == 0 {
+		return -1
+	}
+
+	index := 0
+	for i := 1; i < len(s); i++ {
+		if less(s[index], s[i]) {
+			index = i
+		}
+	}
+	return index
+}
@@ -12,3 +12,13 @@ func TestMax(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestArgMax(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	if i := ArgMax([]int{1, 5, 3, 5}, less); i != 1 {
+		t.Errorf("expected the first index 1 of the ties, but got %d", i)
+	}
+	if i := ArgMax([]int{}, less); i != -1 {
+		t.Errorf("expected -1 for the empty slice, but got %d", i)
+	}
+}
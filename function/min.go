@@ -68,3 +68,21 @@ func MinInSlice(v interface{}) (min interface{}) {
 		panic(errType)
 	}
 }
+
+// ArgMin returns the index of the minimum element in the slice s by less.
+//
+// Return the first index if there are more than one minimum elements,
+// or -1 if s is empty.
+func ArgMin[T any](s []T, less func(a, b T) bool) int {
+	if len(s) == 0 {
+		return -1
+	}
+
+	index := 0
+	for i := 1; i < len(s); i++ {
+		if less(s[i], s[index]) {
+			index = i
+		}
+	}
+	return index
+}
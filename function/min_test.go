@@ -14,3 +14,13 @@ func TestMin(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestArgMin(t *testing.T) {
+	less := func(a, b string) bool { return a < b }
+	if i := ArgMin([]string{"c", "a", "b", "a"}, less); i != 1 {
+		t.Errorf("expected the first index 1 of the ties, but got %d", i)
+	}
+	if i := ArgMin(nil, less); i != -1 {
+		t.Errorf("expected -1 for the empty slice, but got %d", i)
+	}
+}
@@ -0,0 +1,88 @@
+package function
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Lerp returns the linear interpolation between a and b by t,
+// that's, a when t is 0, and b when t is 1.
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// Normalize maps v from the range [min, max] to [0, 1].
+//
+// v out of the range is mapped out of [0, 1] linearly.
+// If the range is degenerate, that's, min == max, return 0.
+func Normalize(v, min, max float64) float64 {
+	if min == max {
+		return 0
+	}
+	return (v - min) / (max - min)
+}
+
+// Map remaps v from the range [inMin, inMax] to [outMin, outMax] linearly.
+//
+// If the input range is degenerate, that's, inMin == inMax, return outMin.
+func Map(v, inMin, inMax, outMin, outMax float64) float64 {
+	return Lerp(outMin, outMax, Normalize(v, inMin, inMax))
+}
+
+// toFloat64 converts the number v to float64, and returns false if v is not
+// a number, that's, the kind of int, uint or float.
+func toFloat64(v interface{}) (float64, bool) {
+	switch _v := v.(type) {
+	case float64:
+		return _v, true
+	case int:
+		return float64(_v), true
+	case nil:
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// mustFloat64 is the same as toFloat64, but panics if v is not a number.
+func mustFloat64(v interface{}) float64 {
+	f, ok := toFloat64(v)
+	if !ok {
+		panic(fmt.Errorf("the value is not a number: %v", v))
+	}
+	return f
+}
+
+// ClosestTo returns the candidate closest to target, that's, the one with
+// the smallest absolute difference, which are compared as float64.
+//
+// If there are more than one closest candidates, return the smallest one,
+// and the first one if they are equal. Return nil if there is no candidate.
+//
+// If candidates is not a slice or array type, or target or any candidate
+// is not a number, it will panic.
+func ClosestTo(target interface{}, candidates interface{}) (closest interface{}) {
+	_target := mustFloat64(target)
+
+	var closestValue, minDiff float64
+	for i, candidate := range interfaces(candidates) {
+		value := mustFloat64(candidate)
+		diff := math.Abs(value - _target)
+		if i == 0 || diff < minDiff || (diff == minDiff && value < closestValue) {
+			closest, closestValue, minDiff = candidate, value, diff
+		}
+	}
+	return
+}
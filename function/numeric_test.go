@@ -0,0 +1,40 @@
+package function
+
+import (
+	"fmt"
+)
+
+func ExampleLerp() {
+	fmt.Println(Lerp(10, 20, 0), Lerp(10, 20, 0.25), Lerp(10, 20, 1))
+
+	// Output:
+	// 10 12.5 20
+}
+
+func ExampleNormalize() {
+	fmt.Println(Normalize(15, 10, 20), Normalize(25, 10, 20), Normalize(5, 5, 5))
+
+	// Output:
+	// 0.5 1.5 0
+}
+
+func ExampleMap() {
+	fmt.Println(Map(50, 0, 100, 32, 212), Map(1, 1, 1, 32, 212))
+
+	// Output:
+	// 122 32
+}
+
+func ExampleClosestTo() {
+	retentions := []int{1, 7, 30, 90}
+	fmt.Println(ClosestTo(5, retentions))
+	fmt.Println(ClosestTo(60, retentions))
+	fmt.Println(ClosestTo(4.0, retentions))
+	fmt.Println(ClosestTo(1, []float64{}))
+
+	// Output:
+	// 7
+	// 30
+	// 1
+	// <nil>
+}
@@ -0,0 +1,51 @@
+package function
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error converted from a recovered panic.
+type PanicError struct {
+	value interface{}
+	stack []byte
+}
+
+// NewPanicError returns a new PanicError with the recovered value,
+// which captures the stack of the current goroutine.
+//
+// It should be called in the deferred function recovering the panic.
+func NewPanicError(value interface{}) *PanicError {
+	return &PanicError{value: value, stack: debug.Stack()}
+}
+
+// Value returns the original recovered value.
+func (e *PanicError) Value() interface{} {
+	return e.value
+}
+
+// Stack returns the stack trace captured when recovering the panic.
+func (e *PanicError) Stack() []byte {
+	return e.stack
+}
+
+// Error implements the interface error, which only contains the value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// Invoke calls fn synchronously, and recovers the panic of fn and returns it
+// as *PanicError, which carries the recovered value and the stack trace
+// separately, such as at the boundary of the RPC handler.
+//
+// Return nil if fn does not panic.
+func Invoke(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewPanicError(r)
+		}
+	}()
+
+	fn()
+	return
+}
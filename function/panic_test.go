@@ -0,0 +1,29 @@
+package function
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestInvoke(t *testing.T) {
+	if err := Invoke(func() {}); err != nil {
+		t.Errorf("expected nil, but got %v", err)
+	}
+
+	err := Invoke(func() { panic("bad request") })
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *PanicError, but got %v", err)
+	}
+
+	if perr.Value() != "bad request" {
+		t.Errorf("expected the value '%s', but got '%v'", "bad request", perr.Value())
+	}
+	if err.Error() != "panic: bad request" {
+		t.Errorf("unexpected the error message '%s'", err.Error())
+	}
+	if !bytes.Contains(perr.Stack(), []byte("TestInvoke")) {
+		t.Errorf("expected the stack to contain the caller, but got:\n%s", perr.Stack())
+	}
+}
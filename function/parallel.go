@@ -0,0 +1,118 @@
+package function
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ForEachParallel calls fn for each element of the slice or array by
+// concurrency goroutines at most, and waits for all of them to finish.
+//
+// Unlike the fail-fast TryMap, it runs fn for all the elements, and returns
+// the errors of all the failed elements as MultiError in the order of
+// the elements, each of which is wrapped with the index of the element.
+// If fn panics, the panic is recovered and converted to *PanicError.
+// Return nil if all succeed.
+//
+// If concurrency is less than 1, it's 1. If slice is not a slice or array
+// type, it will panic.
+func ForEachParallel(slice interface{}, concurrency int, fn func(interface{}) error) error {
+	return forEachParallel(context.Background(), slice, concurrency, false,
+		func(_ context.Context, v interface{}) error { return fn(v) })
+}
+
+// ForEachParallelContext is the same as ForEachParallel, but stops early
+// on the first error, that's, the context passed to fn is canceled,
+// and the rest elements are not processed. It also stops when ctx is done.
+//
+// It returns the first error, which is wrapped with the index of the element,
+// or the error of ctx if it's done before all the elements are processed.
+func ForEachParallelContext(ctx context.Context, slice interface{}, concurrency int,
+	fn func(context.Context, interface{}) error) error {
+	return forEachParallel(ctx, slice, concurrency, true, fn)
+}
+
+type indexedError struct {
+	index int
+	err   error
+}
+
+func forEachParallel(ctx context.Context, slice interface{}, concurrency int,
+	failfast bool, fn func(context.Context, interface{}) error) error {
+
+	values := interfaces(slice)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lock sync.Mutex
+	var errs []indexedError
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(values); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if err := callParallel(ctx, fn, values[index]); err != nil {
+					lock.Lock()
+					errs = append(errs, indexedError{index: index, err: err})
+					lock.Unlock()
+					if failfast {
+						cancel()
+					}
+				}
+			}
+		}()
+	}
+
+	var ctxErr error
+loop:
+	for i := range values {
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			break
+		}
+
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if len(errs) == 0 {
+		if failfast {
+			return ctxErr
+		}
+		return nil
+	}
+
+	if failfast {
+		return fmt.Errorf("element %d: %w", errs[0].index, errs[0].err)
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].index < errs[j].index })
+	merr := make(MultiError, len(errs))
+	for i, e := range errs {
+		merr[i] = fmt.Errorf("element %d: %w", e.index, e.err)
+	}
+	return merr
+}
+
+func callParallel(ctx context.Context, fn func(context.Context, interface{}) error,
+	v interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewPanicError(r)
+		}
+	}()
+	return fn(ctx, v)
+}
@@ -0,0 +1,91 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachParallel(t *testing.T) {
+	var running, maxRunning, calls int32
+	err := ForEachParallel(Range(0, 20, 1), 3, func(v interface{}) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(time.Millisecond)
+
+		switch i := v.(int); {
+		case i == 7:
+			panic("boom")
+		case i%5 == 0:
+			return fmt.Errorf("failed %d", i)
+		}
+		return nil
+	})
+
+	if calls != 20 {
+		t.Errorf("expected 20 calls, but got %d", calls)
+	}
+	if maxRunning > 3 {
+		t.Errorf("expected 3 goroutines at most, but got %d", maxRunning)
+	}
+
+	var errs MultiError
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected MultiError, but got %v", err)
+	}
+	expected := "element 0: failed 0; element 5: failed 5; element 7: panic: boom; " +
+		"element 10: failed 10; element 15: failed 15"
+	if err.Error() != expected {
+		t.Errorf("expected '%s', but got '%s'", expected, err)
+	}
+
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value() != "boom" {
+		t.Errorf("expected the wrapped *PanicError, but got %v", perr)
+	}
+
+	if err = ForEachParallel([]int{1, 2}, 0, func(interface{}) error { return nil }); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestForEachParallelContext(t *testing.T) {
+	var calls int32
+	err := ForEachParallelContext(context.Background(), Range(0, 100, 1), 2,
+		func(ctx context.Context, v interface{}) error {
+			atomic.AddInt32(&calls, 1)
+			if v.(int) == 3 {
+				return errors.New("failed")
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Millisecond):
+			}
+			return nil
+		})
+
+	if err == nil || err.Error() != "element 3: failed" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls >= 100 {
+		t.Errorf("expected to stop early, but got %d calls", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ForEachParallelContext(ctx, Range(0, 10, 1), 2,
+		func(context.Context, interface{}) error { return nil })
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, but got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package function
+
+import "reflect"
+
+// Partial binds the first argument of the two-argument function fn to a,
+// and returns the function only taking the second argument.
+func Partial(fn func(a, b interface{}) interface{}, a interface{}) func(interface{}) interface{} {
+	return func(b interface{}) interface{} {
+		return fn(a, b)
+	}
+}
+
+// Bind binds the leading arguments of the function fn to args,
+// and returns the function taking the rest arguments, which calls fn
+// dynamically by Call and returns its results.
+//
+// If fn is not a function, it will panic. And the returned function
+// will panic if failing to call fn, such as the number or the type
+// of the arguments is incorrect.
+func Bind(fn interface{}, args ...interface{}) func(...interface{}) []interface{} {
+	if reflect.ValueOf(fn).Kind() != reflect.Func {
+		panic(ErrNotFunc)
+	}
+
+	return func(rest ...interface{}) []interface{} {
+		_args := make([]interface{}, 0, len(args)+len(rest))
+		_args = append(_args, args...)
+		results, err := Call(fn, append(_args, rest...)...)
+		if err != nil {
+			panic(err)
+		}
+		return results
+	}
+}
@@ -0,0 +1,24 @@
+package function
+
+import (
+	"fmt"
+)
+
+func ExamplePartial() {
+	gt := Partial(func(a, b interface{}) interface{} { return GT(b, a) }, 10)
+	fmt.Println(gt(5), gt(15))
+
+	// Output:
+	// false true
+}
+
+func ExampleBind() {
+	join := func(sep string, a, b string) string { return a + sep + b }
+	joinByComma := Bind(join, ",")
+	fmt.Println(joinByComma("a", "b")[0])
+	fmt.Println(Bind(join, "-", "x")("y")[0])
+
+	// Output:
+	// a,b
+	// x-y
+}
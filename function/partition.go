@@ -0,0 +1,18 @@
+package function
+
+// Partition splits the slice s into the elements satisfying pred and the rest
+// in one pass, which keeps the order of the elements within each group.
+//
+// The results are always non-nil, and s is not modified.
+func Partition[T any](s []T, pred func(T) bool) (matched, rest []T) {
+	matched = make([]T, 0, len(s))
+	rest = make([]T, 0, len(s))
+	for _, v := range s {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return
+}
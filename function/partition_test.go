@@ -0,0 +1,28 @@
+package function
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	isEven := func(v int) bool { return v%2 == 0 }
+	tests := []struct {
+		s       []int
+		matched []int
+		rest    []int
+	}{
+		{[]int{2, 4, 6}, []int{2, 4, 6}, []int{}},
+		{[]int{1, 3, 5}, []int{}, []int{1, 3, 5}},
+		{[]int{5, 2, 3, 8, 1, 4}, []int{2, 8, 4}, []int{5, 3, 1}},
+		{nil, []int{}, []int{}},
+	}
+
+	for _, test := range tests {
+		matched, rest := Partition(test.s, isEven)
+		if !reflect.DeepEqual(matched, test.matched) || !reflect.DeepEqual(rest, test.rest) {
+			t.Errorf("%v: expected %v and %v, but got %v and %v",
+				test.s, test.matched, test.rest, matched, rest)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package function
+
+// Pipeline wires a series of the channel-transforming stages into one,
+// that's, the output of a stage is the input of the next.
+//
+// Each stage runs in its own goroutine, and the unbuffered channels between
+// the stages supply the natural backpressure. Closing the input cascades
+// through the stages to close the final output, if every stage closes its
+// output when its input is closed, such as Stage.
+//
+// If a stage panics, the panic is recovered and surfaced as a *PanicError
+// sent to the output, then the input of the stage is drained so that
+// the upstream stages are not blocked. For a panic in the goroutine started
+// by the stage itself, it's the responsibility of the stage, and Stage
+// recovers it per element.
+func Pipeline(stages ...func(in <-chan interface{}) <-chan interface{}) func(in <-chan interface{}) <-chan interface{} {
+	return func(in <-chan interface{}) <-chan interface{} {
+		for _, stage := range stages {
+			in = runStage(stage, in)
+		}
+		return in
+	}
+}
+
+func runStage(stage func(<-chan interface{}) <-chan interface{}, in <-chan interface{}) <-chan interface{} {
+	out := make(chan interface{})
+	go func() {
+		defer close(out)
+		defer func() {
+			if v := recover(); v != nil {
+				out <- NewPanicError(v)
+				for range in {
+				}
+			}
+		}()
+
+		for v := range stage(in) {
+			out <- v
+		}
+	}()
+	return out
+}
+
+// Stage returns a pipeline stage, which maps each element of the input by fn
+// in a new goroutine, and closes the output when the input is closed.
+//
+// If fn panics for an element, the panic is recovered and a *PanicError is
+// sent to the output instead of the result.
+func Stage(fn func(interface{}) interface{}) func(in <-chan interface{}) <-chan interface{} {
+	return func(in <-chan interface{}) <-chan interface{} {
+		out := make(chan interface{})
+		go func() {
+			defer close(out)
+			for v := range in {
+				out <- callStage(fn, v)
+			}
+		}()
+		return out
+	}
+}
+
+func callStage(fn func(interface{}) interface{}, v interface{}) (result interface{}) {
+	defer func() {
+		if v := recover(); v != nil {
+			result = NewPanicError(v)
+		}
+	}()
+	return fn(v)
+}
@@ -0,0 +1,62 @@
+package function
+
+import (
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	pipeline := Pipeline(
+		Stage(func(v interface{}) interface{} { return v.(int) * 2 }),
+		Stage(func(v interface{}) interface{} {
+			if v.(int) == 6 {
+				panic("bad value")
+			}
+			return v.(int) + 1
+		}),
+	)
+
+	in := make(chan interface{})
+	out := pipeline(in)
+	go func() {
+		for i := 1; i <= 4; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	var results []interface{}
+	for v := range out {
+		results = append(results, v)
+	}
+
+	if len(results) != 4 || results[0] != 3 || results[1] != 5 || results[3] != 9 {
+		t.Fatalf("unexpected results: %v", results)
+	}
+	if err, ok := results[2].(*PanicError); !ok || err.Value() != "bad value" {
+		t.Errorf("expected the panic error, but got %v", results[2])
+	}
+}
+
+func TestPipelineStagePanic(t *testing.T) {
+	pipeline := Pipeline(func(in <-chan interface{}) <-chan interface{} {
+		panic("bad stage")
+	})
+
+	in := make(chan interface{})
+	out := pipeline(in)
+	go func() {
+		in <- 1 // The input is drained, so it's not blocked.
+		close(in)
+	}()
+
+	var results []interface{}
+	for v := range out {
+		results = append(results, v)
+	}
+	if len(results) != 1 {
+		t.Fatalf("unexpected results: %v", results)
+	}
+	if err, ok := results[0].(*PanicError); !ok || err.Value() != "bad stage" {
+		t.Errorf("expected the panic error, but got %v", results[0])
+	}
+}
@@ -0,0 +1,56 @@
+package function
+
+import "sort"
+
+// RankCompetition returns the ranks of the elements of slice in the ascending
+// order by Compare, which starts from 1, and the ith rank is for the ith
+// element. It's the standard competition ranking, or "1224" ranking, that's,
+// the equal elements share the lowest rank of them, and the rank after them
+// skips the shared ones. For example,
+//
+//	RankCompetition([]int{10, 20, 20, 30}) // [1 2 2 4]
+//
+// The slice is not modified. For the descending ranking, such as
+// the leaderboard whose higher score is better, compare the elements
+// in reverse, for example, by negating the scores.
+//
+// If slice is not a slice or array type, or the elements cannot be compared
+// by Compare, it will panic.
+func RankCompetition(slice interface{}) []int {
+	return rank(slice, false)
+}
+
+// RankDense is the same as RankCompetition, but returns the dense ranking,
+// or "1223" ranking, that's, the rank after the equal elements is the next
+// one without skipping. For example,
+//
+//	RankDense([]int{10, 20, 20, 30}) // [1 2 2 3]
+func RankDense(slice interface{}) []int {
+	return rank(slice, true)
+}
+
+func rank(slice interface{}, dense bool) []int {
+	values := interfaces(slice)
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return LT(values[indexes[i]], values[indexes[j]])
+	})
+
+	ranks := make([]int, len(values))
+	for i, index := range indexes {
+		switch {
+		case i == 0:
+			ranks[index] = 1
+		case EQ(values[index], values[indexes[i-1]]):
+			ranks[index] = ranks[indexes[i-1]]
+		case dense:
+			ranks[index] = ranks[indexes[i-1]] + 1
+		default:
+			ranks[index] = i + 1
+		}
+	}
+	return ranks
+}
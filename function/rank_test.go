@@ -0,0 +1,32 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleRankCompetition() {
+	fmt.Println(RankCompetition([]int{30, 10, 20, 20}))
+	fmt.Println(RankDense([]int{30, 10, 20, 20}))
+
+	// Output:
+	// [4 1 2 2]
+	// [3 1 2 2]
+}
+
+func TestRank(t *testing.T) {
+	values := []string{"b", "a", "c", "a", "b", "d"}
+	if ranks := RankCompetition(values); !reflect.DeepEqual(ranks, []int{3, 1, 5, 1, 3, 6}) {
+		t.Errorf("unexpected the competition ranks %v", ranks)
+	}
+	if ranks := RankDense(values); !reflect.DeepEqual(ranks, []int{2, 1, 3, 1, 2, 4}) {
+		t.Errorf("unexpected the dense ranks %v", ranks)
+	}
+	if !reflect.DeepEqual(values, []string{"b", "a", "c", "a", "b", "d"}) {
+		t.Errorf("expected the slice not to be modified, but got %v", values)
+	}
+	if ranks := RankDense([]int{}); len(ranks) != 0 {
+		t.Errorf("expected no ranks, but got %v", ranks)
+	}
+}
@@ -0,0 +1,44 @@
+package function
+
+// CumSum returns the running sums of the numeric slice or array, that's,
+// the ith result is the sum of the elements from 0 to i.
+//
+// The elements may be any kind of int, uint or float, which are converted
+// to float64 before being added. Return an empty slice if slice is empty.
+//
+// If slice is not a slice or array type, or any element is not a number,
+// it will panic.
+func CumSum(slice interface{}) []float64 {
+	values := interfaces(slice)
+	sums := make([]float64, len(values))
+
+	var sum float64
+	for i, v := range values {
+		sum += mustFloat64(v)
+		sums[i] = sum
+	}
+	return sums
+}
+
+// Scan is the same as the reduction of the slice or array by fn from init,
+// but returns all the intermediate accumulated values, that's, the ith result
+// is the accumulated value after the ith element, and the last one is
+// the reduced value.
+//
+// init is not contained in the result. Return an empty slice if slice
+// is empty.
+//
+// If slice is not a slice or array type, it will panic.
+func Scan(slice interface{}, init interface{},
+	fn func(acc, x interface{}) interface{}) []interface{} {
+
+	values := interfaces(slice)
+	results := make([]interface{}, len(values))
+
+	acc := init
+	for i, v := range values {
+		acc = fn(acc, v)
+		results[i] = acc
+	}
+	return results
+}
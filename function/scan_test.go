@@ -0,0 +1,30 @@
+package function
+
+import (
+	"fmt"
+)
+
+func ExampleCumSum() {
+	fmt.Println(CumSum([]int{1, 2, 3, 4}))
+	fmt.Println(CumSum([]float32{0.5, 1.5}))
+	fmt.Println(CumSum([]uint8{}))
+
+	// Output:
+	// [1 3 6 10]
+	// [0.5 2]
+	// []
+}
+
+func ExampleScan() {
+	fmt.Println(Scan([]string{"a", "b", "c"}, "", func(acc, x interface{}) interface{} {
+		return acc.(string) + x.(string)
+	}))
+
+	fmt.Println(Scan([]int{3, 1, 4, 1, 5}, 0, func(acc, x interface{}) interface{} {
+		return Max(acc, x)
+	}))
+
+	// Output:
+	// [a ab abc]
+	// [3 3 4 4 5]
+}
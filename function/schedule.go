@@ -0,0 +1,52 @@
+package function
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Every runs fn every d in a background goroutine, and returns the function
+// to stop it, which waits for the in-flight invocation of fn to finish.
+//
+// If fn runs longer than d, the missed ticks are skipped, not queued.
+func Every(d time.Duration, fn func()) (stop func()) {
+	return EveryContext(d, func(context.Context) { fn() })
+}
+
+// EveryContext is the same as Every, but passes a context into fn,
+// which is cancelled when stopping.
+func EveryContext(d time.Duration, fn func(ctx context.Context)) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(d)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			fn(ctx)
+
+			// Skip the tick missed during running fn.
+			select {
+			case <-ticker.C:
+			default:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package function
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	var count int32
+	stop := Every(5*time.Millisecond, func() { atomic.AddInt32(&count, 1) })
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	n := atomic.LoadInt32(&count)
+	if n == 0 {
+		t.Error("expected fn to be run")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&count) != n {
+		t.Error("expected fn not to be run after stopping")
+	}
+	stop()
+}
+
+func TestEveryContextWaitsInFlight(t *testing.T) {
+	var finished, count int32
+	stop := EveryContext(time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+		select {
+		case <-ctx.Done():
+		case <-time.After(30 * time.Millisecond):
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+	})
+
+	time.Sleep(5 * time.Millisecond)
+	stop()
+	if atomic.LoadInt32(&finished) != 1 {
+		t.Error("expected stop to wait for the in-flight invocation")
+	}
+	if n := atomic.LoadInt32(&count); n != 1 {
+		t.Errorf("expected the ticks to be skipped, but fn was run %d times", n)
+	}
+}
@@ -0,0 +1,78 @@
+package function
+
+import (
+	"math"
+	"sort"
+)
+
+// SortedContains returns true if v is in the slice, which uses the binary
+// search by Compare, so it's O(log n) rather than the linear InSlice.
+//
+// Notice: the slice must be sorted in the ascending order by Compare,
+// or the result is undefined.
+func SortedContains(slice []interface{}, v interface{}) bool {
+	_, found := BinarySearch(slice, v)
+	return found
+}
+
+// BinarySearch searches target in the slice by Compare, and returns
+// the index of the first element equal to target and true if found.
+// Or returns the insertion point where target would be inserted to keep
+// the slice sorted and false.
+//
+// Notice: the slice must be sorted in the ascending order by Compare,
+// or the result is undefined.
+func BinarySearch(slice []interface{}, target interface{}) (index int, found bool) {
+	index = sort.Search(len(slice), func(i int) bool { return Compare(slice[i], target) >= 0 })
+	found = index < len(slice) && Compare(slice[index], target) == 0
+	return
+}
+
+// NearestIndex returns the index of the element closest to target in the
+// slice, which locates the insertion point of target by the binary search and
+// then compares the two neighbors, so it's O(log n) rather than ClosestTo.
+//
+// The elements and target are compared as float64. If the two neighbors are
+// equidistant to target, return the lower index. Return -1 if slice is empty.
+//
+// Notice: the slice must be sorted in the ascending order, and target and
+// all the elements must be numbers, or it will panic.
+func NearestIndex(slice []interface{}, target interface{}) int {
+	_len := len(slice)
+	if _len == 0 {
+		return -1
+	}
+
+	_target := mustFloat64(target)
+	index := sort.Search(_len, func(i int) bool { return mustFloat64(slice[i]) >= _target })
+	if index == 0 {
+		return 0
+	} else if index == _len {
+		return _len - 1
+	}
+
+	lower := math.Abs(_target - mustFloat64(slice[index-1]))
+	upper := math.Abs(mustFloat64(slice[index]) - _target)
+	if lower <= upper {
+		return index - 1
+	}
+	return index
+}
+
+// IndexOf returns the index of the first element in slice equal to target
+// by Compare, or -1 if not found. So the element implementing the interface
+// Comparer is compared by its method Compare with target.
+//
+// It's a linear search, and the element which cannot be compared with target,
+// such as the one of another type in the heterogeneous slice, is treated as
+// unequal rather than panicking.
+//
+// If slice is not a slice or array type, it will panic.
+func IndexOf(slice interface{}, target interface{}) int {
+	for i, v := range interfaces(slice) {
+		if r, err := CompareE(v, target); err == nil && r == 0 {
+			return i
+		}
+	}
+	return -1
+}
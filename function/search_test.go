@@ -0,0 +1,77 @@
+package function
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleSortedContains() {
+	allows := []interface{}{"a", "c", "e", "g"}
+	fmt.Println(SortedContains(allows, "e"))
+	fmt.Println(SortedContains(allows, "f"))
+	fmt.Println(SortedContains(allows, "h"))
+	fmt.Println(SortedContains(nil, "a"))
+
+	// Output:
+	// true
+	// false
+	// false
+	// false
+}
+
+func ExampleBinarySearch() {
+	table := []interface{}{10, 20, 20, 30}
+	fmt.Println(BinarySearch(table, 20))
+	fmt.Println(BinarySearch(table, 25))
+	fmt.Println(BinarySearch(table, 5))
+	fmt.Println(BinarySearch(table, 40))
+	fmt.Println(BinarySearch(nil, 1))
+
+	// Output:
+	// 1 true
+	// 3 false
+	// 0 false
+	// 4 false
+	// 0 false
+}
+
+func ExampleNearestIndex() {
+	samples := []interface{}{10, 20, 30, 45.5}
+	fmt.Println(NearestIndex(samples, 22))
+	fmt.Println(NearestIndex(samples, 25)) // Equidistant
+	fmt.Println(NearestIndex(samples, 40.0))
+	fmt.Println(NearestIndex(samples, -5))
+	fmt.Println(NearestIndex(samples, 100))
+	fmt.Println(NearestIndex(nil, 1))
+
+	// Output:
+	// 1
+	// 1
+	// 3
+	// 0
+	// 3
+	// -1
+}
+
+type searchCaseless string
+
+func (s searchCaseless) Compare(v interface{}) int {
+	other, ok := v.(searchCaseless)
+	if !ok {
+		return 1
+	}
+	return strings.Compare(strings.ToLower(string(s)), strings.ToLower(string(other)))
+}
+
+func ExampleIndexOf() {
+	fmt.Println(IndexOf([]int{3, 1, 4, 1}, 1))
+	fmt.Println(IndexOf([]int{3, 1, 4, 1}, 5))
+	fmt.Println(IndexOf([]interface{}{"a", 1, int64(2), 2}, 2))
+	fmt.Println(IndexOf([]searchCaseless{"Foo", "Bar"}, searchCaseless("BAR")))
+
+	// Output:
+	// 1
+	// -1
+	// 3
+	// 1
+}
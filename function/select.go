@@ -0,0 +1,59 @@
+package function
+
+// Select returns the kth smallest element (0-based) of the slice or array
+// by the quickselect, which compares the elements by Compare, so it's
+// O(n) on average without sorting the whole slice.
+//
+// The slice is not modified, because the selection operates on the copy.
+//
+// If slice is not a slice or array type, it will panic with
+// ErrNotSliceOrArray, and if k is out of [0, len(slice)), panic with
+// ErrInvalidIndex.
+func Select(slice interface{}, k int) interface{} {
+	values := append([]interface{}(nil), interfaces(slice)...)
+	if k < 0 || k >= len(values) {
+		panic(ErrInvalidIndex)
+	}
+
+	left, right := 0, len(values)-1
+	for left < right {
+		// Use the median of three as the pivot to avoid the worst case
+		// for the sorted input.
+		mid := left + (right-left)/2
+		if LT(values[mid], values[left]) {
+			values[mid], values[left] = values[left], values[mid]
+		}
+		if LT(values[right], values[left]) {
+			values[right], values[left] = values[left], values[right]
+		}
+		if LT(values[right], values[mid]) {
+			values[right], values[mid] = values[mid], values[right]
+		}
+		pivot := values[mid]
+
+		i, j := left, right
+		for i <= j {
+			for LT(values[i], pivot) {
+				i++
+			}
+			for LT(pivot, values[j]) {
+				j--
+			}
+			if i <= j {
+				values[i], values[j] = values[j], values[i]
+				i++
+				j--
+			}
+		}
+
+		switch {
+		case k <= j:
+			right = j
+		case k >= i:
+			left = i
+		default:
+			return values[k]
+		}
+	}
+	return values[k]
+}
@@ -0,0 +1,44 @@
+package function
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	values := []int{5, 3, 9, 1, 7, 3, 8}
+	original := append([]int(nil), values...)
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	for k, expected := range sorted {
+		if v := Select(values, k); v != expected {
+			t.Errorf("%d: expected %d, but got %v", k, expected, v)
+		}
+	}
+	if !reflect.DeepEqual(values, original) {
+		t.Errorf("the slice is modified: %v", values)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	floats := make([]interface{}, 1000)
+	for i := range floats {
+		floats[i] = float64(random.Intn(100))
+	}
+	sortedFloats := append([]interface{}(nil), floats...)
+	sort.Slice(sortedFloats, LessFunc(sortedFloats))
+	for _, k := range []int{0, 499, 500, 999} {
+		if v := Select(floats, k); v != sortedFloats[k] {
+			t.Errorf("%d: expected %v, but got %v", k, sortedFloats[k], v)
+		}
+	}
+
+	defer func() {
+		if err := recover(); err != ErrInvalidIndex {
+			t.Errorf("expected ErrInvalidIndex, but got %v", err)
+		}
+	}()
+	Select([]int{}, 0)
+}
@@ -167,3 +167,78 @@ func InSlice(v interface{}, slice interface{}) bool {
 	}
 	return false
 }
+
+// interfaces returns the elements of the slice or array as []interface{}.
+//
+// If slice is not a slice or array type, it will panic.
+func interfaces(slice interface{}) []interface{} {
+	switch s := slice.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return s
+	}
+
+	v := reflect.ValueOf(slice)
+	if kind := v.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		panic(ErrNotSliceOrArray)
+	}
+
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
+
+// First returns the first element of the slice or array and true.
+//
+// It returns (nil, false) if slice is nil or empty, or not a slice or array.
+func First(slice interface{}) (interface{}, bool) {
+	return elementAt(slice, 0)
+}
+
+// Last returns the last element of the slice or array and true.
+//
+// It returns (nil, false) if slice is nil or empty, or not a slice or array.
+func Last(slice interface{}) (interface{}, bool) {
+	return elementAt(slice, -1)
+}
+
+// FirstOr is the same as First, but returns def instead if not found.
+func FirstOr(slice, def interface{}) interface{} {
+	if v, ok := First(slice); ok {
+		return v
+	}
+	return def
+}
+
+// LastOr is the same as Last, but returns def instead if not found.
+func LastOr(slice, def interface{}) interface{} {
+	if v, ok := Last(slice); ok {
+		return v
+	}
+	return def
+}
+
+// elementAt returns the ith element of the slice or array, and the negative
+// index counts from the end.
+func elementAt(slice interface{}, i int) (interface{}, bool) {
+	if slice == nil {
+		return nil, false
+	}
+
+	s := reflect.ValueOf(slice)
+	if kind := s.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return nil, false
+	}
+
+	_len := s.Len()
+	if i < 0 {
+		i += _len
+	}
+	if i < 0 || i >= _len {
+		return nil, false
+	}
+	return s.Index(i).Interface(), true
+}
@@ -43,3 +43,38 @@ func ExampleInSlice() {
 	// true
 	// false
 }
+
+func ExampleFirst() {
+	fmt.Println(First([]int{1, 2, 3}))
+	fmt.Println(First([]int{}))
+	fmt.Println(First(nil))
+
+	// Output:
+	// 1 true
+	// <nil> false
+	// <nil> false
+}
+
+func ExampleLast() {
+	fmt.Println(Last([]string{"a", "b", "c"}))
+	fmt.Println(Last([]string(nil)))
+	fmt.Println(Last([2]int{1, 2}))
+
+	// Output:
+	// c true
+	// <nil> false
+	// 2 true
+}
+
+func ExampleFirstOr() {
+	fmt.Println(FirstOr([]int{1, 2, 3}, -1))
+	fmt.Println(FirstOr([]int{}, -1))
+	fmt.Println(LastOr([]int{1, 2, 3}, -1))
+	fmt.Println(LastOr("not a slice", -1))
+
+	// Output:
+	// 1
+	// -1
+	// 3
+	// -1
+}
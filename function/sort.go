@@ -0,0 +1,209 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SortBy sorts the slice stably by the keys extracted by the function key,
+// which are compared by Compare.
+//
+// It uses sort.SliceStable, so the elements with the equal keys are
+// guaranteed to keep their original relative order.
+//
+// key returns the key of the ith element of the slice, which should access
+// the element by the index at the time of the call, because the slice is
+// sorted in place.
+//
+// If slice is not a slice type, it will panic.
+func SortBy(slice interface{}, key func(i int) interface{}) {
+	sort.SliceStable(slice, func(i, j int) bool { return LT(key(i), key(j)) })
+}
+
+// SortByUnstable is the same as SortBy, but uses sort.Slice, which is faster
+// but doesn't guarantee the relative order of the elements with the equal
+// keys.
+func SortByUnstable(slice interface{}, key func(i int) interface{}) {
+	sort.Slice(slice, func(i, j int) bool { return LT(key(i), key(j)) })
+}
+
+// SortStableBy sorts the slice stably by the comparison function cmp,
+// which returns a negative integer if a is less than b, 0 if equal,
+// or a positive integer if greater, like Compare.
+//
+// It uses sort.Stable, so the elements comparing equal are guaranteed
+// to keep their original relative order, for example, the order of
+// the prior sort by another field.
+//
+// If slice is not a slice type, it will panic.
+func SortStableBy(slice interface{}, cmp func(a, b interface{}) int) {
+	sort.Stable(newCmpSlice(slice, cmp))
+}
+
+// SortUnstableBy is the same as SortStableBy, but uses sort.Sort, which
+// doesn't guarantee the relative order of the elements comparing equal.
+func SortUnstableBy(slice interface{}, cmp func(a, b interface{}) int) {
+	sort.Sort(newCmpSlice(slice, cmp))
+}
+
+// SortByFields sorts the slice stably by the keys extracted by the functions
+// keys in the priority order, that's, the elements are compared by the first
+// key, then the second key if the first keys are equal, and so on. The keys
+// are compared by Compare. For example, sort the records by date then name:
+//
+//	SortByFields(records,
+//	    func(v interface{}) interface{} { return v.(Record).Date },
+//	    func(v interface{}) interface{} { return v.(Record).Name })
+//
+// If slice is not a slice type, or the keys cannot be compared, it will panic.
+func SortByFields(slice interface{}, keys ...func(interface{}) interface{}) {
+	SortStableBy(slice, compareFields(keys, false))
+}
+
+// SortByFieldsDesc is the same as SortByFields, but sorts the slice
+// in the descending order by all the keys.
+func SortByFieldsDesc(slice interface{}, keys ...func(interface{}) interface{}) {
+	SortStableBy(slice, compareFields(keys, true))
+}
+
+func compareFields(keys []func(interface{}) interface{}, desc bool) func(a, b interface{}) int {
+	return func(a, b interface{}) int {
+		for _, key := range keys {
+			if r := Compare(key(a), key(b)); r != 0 {
+				if desc {
+					return -r
+				}
+				return r
+			}
+		}
+		return 0
+	}
+}
+
+// cmpSlice implements the interface sort.Interface by the reflection,
+// which compares the elements by cmp.
+type cmpSlice struct {
+	v    reflect.Value
+	swap func(i, j int)
+	cmp  func(a, b interface{}) int
+}
+
+func newCmpSlice(slice interface{}, cmp func(a, b interface{}) int) cmpSlice {
+	return cmpSlice{v: reflect.ValueOf(slice), swap: reflect.Swapper(slice), cmp: cmp}
+}
+
+func (s cmpSlice) Len() int      { return s.v.Len() }
+func (s cmpSlice) Swap(i, j int) { s.swap(i, j) }
+func (s cmpSlice) Less(i, j int) bool {
+	return s.cmp(s.v.Index(i).Interface(), s.v.Index(j).Interface()) < 0
+}
+
+// SortParallel sorts keySlice stably by Compare, and reorders every slice
+// in others in the same way, so the slices as the columns keep aligned,
+// for example, sorting the names and reordering the ages along with them.
+//
+// If keySlice or any of others is not a slice type, or their lengths are not
+// the same, or the elements of keySlice cannot be compared, it will panic.
+func SortParallel(keySlice interface{}, others ...interface{}) {
+	keys := reflect.ValueOf(keySlice)
+	if keys.Kind() != reflect.Slice {
+		panic(fmt.Errorf("the value is not a slice: %T", keySlice))
+	}
+
+	swaps := make([]func(i, j int), 0, len(others)+1)
+	swaps = append(swaps, reflect.Swapper(keySlice))
+	for i, other := range others {
+		v := reflect.ValueOf(other)
+		if v.Kind() != reflect.Slice {
+			panic(fmt.Errorf("the value is not a slice: %T", other))
+		} else if v.Len() != keys.Len() {
+			panic(fmt.Errorf("the length of slice %d is %d, not %d", i, v.Len(), keys.Len()))
+		}
+		swaps = append(swaps, reflect.Swapper(other))
+	}
+
+	sort.Stable(parallelSlice{keys: keys, swaps: swaps})
+}
+
+// parallelSlice implements the interface sort.Interface, which compares
+// the keys and swaps all the slices together.
+type parallelSlice struct {
+	keys  reflect.Value
+	swaps []func(i, j int)
+}
+
+func (s parallelSlice) Len() int { return s.keys.Len() }
+func (s parallelSlice) Less(i, j int) bool {
+	return LT(s.keys.Index(i).Interface(), s.keys.Index(j).Interface())
+}
+func (s parallelSlice) Swap(i, j int) {
+	for _, swap := range s.swaps {
+		swap(i, j)
+	}
+}
+
+// SortedSet returns the sorted unique values, which are sorted stably
+// and deduplicated by Compare, that's, the first one of the equal values
+// is kept.
+//
+// If the values cannot be compared, it will panic.
+func SortedSet(vals ...interface{}) []interface{} {
+	values := append([]interface{}(nil), vals...)
+	sort.SliceStable(values, func(i, j int) bool { return LT(values[i], values[j]) })
+
+	set := values[:0]
+	for i, v := range values {
+		if i == 0 || NE(v, set[len(set)-1]) {
+			set = append(set, v)
+		}
+	}
+	return set
+}
+
+// LessFunc returns a less function of the slice, which compares the ith and
+// jth elements by Compare, so it's able to be used by sort.Slice, or as
+// the method Less of the type implementing sort.Interface. For example,
+//
+//	sort.Slice(values, LessFunc(values))
+//
+// The elements are accessed at the time of the call, so the function follows
+// the slice sorted in place.
+//
+// If slice is not a slice or array type, it will panic.
+func LessFunc(slice interface{}) func(i, j int) bool {
+	v := reflect.ValueOf(slice)
+	if kind := v.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		panic(ErrNotSliceOrArray)
+	}
+
+	return func(i, j int) bool {
+		return LT(v.Index(i).Interface(), v.Index(j).Interface())
+	}
+}
+
+// Slice is a generic slice with the less function, which implements
+// the interface sort.Interface, so it's able to be sorted without
+// the boilerplate. For example,
+//
+//	sort.Sort(Slice[Record]{Data: records, LessFunc: byName})
+//
+// For the stable sort, use sort.Stable instead:
+//
+//	sort.Stable(Slice[Record]{Data: records, LessFunc: byName})
+//
+// Notice: the less function is the field LessFunc rather than Less,
+// because Less is the method of sort.Interface.
+type Slice[T any] struct {
+	Data     []T
+	LessFunc func(a, b T) bool
+}
+
+// Len implements the interface sort.Interface.
+func (s Slice[T]) Len() int { return len(s.Data) }
+
+// Less implements the interface sort.Interface.
+func (s Slice[T]) Less(i, j int) bool { return s.LessFunc(s.Data[i], s.Data[j]) }
+
+// Swap implements the interface sort.Interface.
+func (s Slice[T]) Swap(i, j int) { s.Data[i], s.Data[j] = s.Data[j], s.Data[i] }
@@ -0,0 +1,154 @@
+package function
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type sortPerson struct {
+	Name string
+	Age  int
+}
+
+func TestSortBy(t *testing.T) {
+	people := []interface{}{
+		sortPerson{"Carol", 30},
+		sortPerson{"Alice", 20},
+		sortPerson{"Bob", 40},
+	}
+	SortBy(people, func(i int) interface{} { return people[i].(sortPerson).Name })
+
+	for i, name := range []string{"Alice", "Bob", "Carol"} {
+		if p := people[i].(sortPerson); p.Name != name {
+			t.Errorf("%d: expected %s, but got %s", i, name, p.Name)
+		}
+	}
+}
+
+func TestSortByStable(t *testing.T) {
+	sorts := map[string]func([]sortPerson){
+		"SortBy": func(people []sortPerson) {
+			SortBy(people, func(i int) interface{} { return people[i].Name })
+		},
+		"SortStableBy": func(people []sortPerson) {
+			SortStableBy(people, func(a, b interface{}) int {
+				return Compare(a.(sortPerson).Name, b.(sortPerson).Name)
+			})
+		},
+	}
+
+	for name, sortPeople := range sorts {
+		people := make([]sortPerson, 100)
+		for i := range people {
+			people[i] = sortPerson{Name: string(rune('a' + i%3)), Age: i}
+		}
+		sortPeople(people)
+
+		for i := 1; i < len(people); i++ {
+			prev, cur := people[i-1], people[i]
+			if prev.Name > cur.Name {
+				t.Fatalf("%s: %d: not sorted: %v, %v", name, i, prev, cur)
+			} else if prev.Name == cur.Name && prev.Age > cur.Age {
+				t.Fatalf("%s: %d: not stable: %v, %v", name, i, prev, cur)
+			}
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	people := []sortPerson{{"a", 3}, {"b", 1}, {"c", 2}, {"d", 1}}
+	byAge := func(a, b sortPerson) bool { return a.Age < b.Age }
+
+	sort.Stable(Slice[sortPerson]{Data: people, LessFunc: byAge})
+	expected := []sortPerson{{"b", 1}, {"d", 1}, {"c", 2}, {"a", 3}}
+	if !reflect.DeepEqual(people, expected) {
+		t.Errorf("expected %v, but got %v", expected, people)
+	}
+
+	sort.Sort(sort.Reverse(Slice[sortPerson]{Data: people, LessFunc: byAge}))
+	if people[0].Age != 3 || people[3].Age != 1 {
+		t.Errorf("unexpected the reversed order: %v", people)
+	}
+}
+
+func TestLessFunc(t *testing.T) {
+	values := []string{"c", "a", "d", "b"}
+	sort.Slice(values, LessFunc(values))
+	if expected := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, but got %v", expected, values)
+	}
+
+	numbers := []interface{}{3.5, 1.0, 2.25}
+	sort.Slice(numbers, LessFunc(numbers))
+	if expected := []interface{}{1.0, 2.25, 3.5}; !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("expected %v, but got %v", expected, numbers)
+	}
+}
+
+func TestSortUnstableBy(t *testing.T) {
+	values := []int{5, 2, 4, 1, 3}
+	SortUnstableBy(values, func(a, b interface{}) int { return Compare(b, a) })
+	if expected := []int{5, 4, 3, 2, 1}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, but got %v", expected, values)
+	}
+
+	SortByUnstable(values, func(i int) interface{} { return values[i] })
+	if expected := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, but got %v", expected, values)
+	}
+}
+
+func TestSortedSet(t *testing.T) {
+	if set := SortedSet(3, 1, 2, 3, 1, 5); !reflect.DeepEqual(set, []interface{}{1, 2, 3, 5}) {
+		t.Errorf("unexpected the set: %v", set)
+	}
+	if set := SortedSet("b", "a", "b", "c", "a"); !reflect.DeepEqual(set, []interface{}{"a", "b", "c"}) {
+		t.Errorf("unexpected the set: %v", set)
+	}
+	if set := SortedSet(); len(set) != 0 {
+		t.Errorf("expected the empty set, but got %v", set)
+	}
+}
+
+func TestSortParallel(t *testing.T) {
+	names := []string{"carol", "alice", "dave", "bob"}
+	ages := []int{35, 30, 40, 25}
+	emails := []string{"c@x", "a@x", "d@x", "b@x"}
+	SortParallel(names, ages, emails)
+
+	if expected := []string{"alice", "bob", "carol", "dave"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, but got %v", expected, names)
+	}
+	if expected := []int{30, 25, 35, 40}; !reflect.DeepEqual(ages, expected) {
+		t.Errorf("expected %v, but got %v", expected, ages)
+	}
+	if expected := []string{"a@x", "b@x", "c@x", "d@x"}; !reflect.DeepEqual(emails, expected) {
+		t.Errorf("expected %v, but got %v", expected, emails)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for the different lengths")
+		}
+	}()
+	SortParallel(names, []int{1, 2})
+}
+
+func TestSortByFields(t *testing.T) {
+	people := []sortPerson{{"Carol", 30}, {"Alice", 30}, {"Bob", 20}, {"Alice", 20}}
+	byAge := func(v interface{}) interface{} { return v.(sortPerson).Age }
+	byName := func(v interface{}) interface{} { return v.(sortPerson).Name }
+
+	SortByFields(people, byAge, byName)
+	expected := []sortPerson{{"Alice", 20}, {"Bob", 20}, {"Alice", 30}, {"Carol", 30}}
+	if !reflect.DeepEqual(people, expected) {
+		t.Errorf("expected %v, but got %v", expected, people)
+	}
+
+	SortByFieldsDesc(people, byAge, byName)
+	expected = []sortPerson{{"Carol", 30}, {"Alice", 30}, {"Bob", 20}, {"Alice", 20}}
+	if !reflect.DeepEqual(people, expected) {
+		t.Errorf("expected %v, but got %v", expected, people)
+	}
+}
@@ -0,0 +1,27 @@
+package function
+
+// Span returns both the minimal and maximal elements of the slice or array
+// in a single pass, which are compared by Compare.
+//
+// If there are more than one minimal or maximal elements, return the first.
+// Return (nil, nil) if the slice is nil or empty like MinInSlice and
+// MaxInSlice.
+//
+// If slice is not a slice or array type, or the elements cannot be compared,
+// it will panic.
+func Span(slice interface{}) (min, max interface{}) {
+	values := interfaces(slice)
+	if len(values) == 0 {
+		return
+	}
+
+	min, max = values[0], values[0]
+	for _, v := range values[1:] {
+		if LT(v, min) {
+			min = v
+		} else if GT(v, max) {
+			max = v
+		}
+	}
+	return
+}
@@ -0,0 +1,18 @@
+package function
+
+import (
+	"fmt"
+)
+
+func ExampleSpan() {
+	fmt.Println(Span([]int{3, 1, 4, 1, 5, 9, 2, 6}))
+	fmt.Println(Span([]float64{2.5}))
+	fmt.Println(Span([]string{"b", "c", "a"}))
+	fmt.Println(Span([]int{}))
+
+	// Output:
+	// 1 9
+	// 2.5 2.5
+	// a c
+	// <nil> <nil>
+}
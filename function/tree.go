@@ -0,0 +1,51 @@
+package function
+
+// WalkTree traverses the tree from root in the depth-first pre-order,
+// that's, it visits a node before its children, and the children in order.
+// children returns the children of the node, and depth of root is 0.
+//
+// If visit returns false, the traversal stops at once.
+//
+// Notice: the cycle is not detected, so the graph with the cycle must not
+// be traversed.
+func WalkTree(root interface{}, children func(interface{}) []interface{},
+	visit func(node interface{}, depth int) bool) {
+	walkTree(root, 0, children, visit)
+}
+
+func walkTree(node interface{}, depth int, children func(interface{}) []interface{},
+	visit func(interface{}, int) bool) bool {
+	if !visit(node, depth) {
+		return false
+	}
+
+	for _, child := range children(node) {
+		if !walkTree(child, depth+1, children, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// WalkTreeBFS is the same as WalkTree, but traverses the tree in
+// the breadth-first order, that's, level by level.
+func WalkTreeBFS(root interface{}, children func(interface{}) []interface{},
+	visit func(node interface{}, depth int) bool) {
+	type item struct {
+		node  interface{}
+		depth int
+	}
+
+	queue := []item{{node: root}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if !visit(current.node, current.depth) {
+			return
+		}
+
+		for _, child := range children(current.node) {
+			queue = append(queue, item{node: child, depth: current.depth + 1})
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package function
+
+import (
+	"fmt"
+	"strings"
+)
+
+type treeNode struct {
+	name     string
+	children []*treeNode
+}
+
+func treeChildren(node interface{}) []interface{} {
+	children := node.(*treeNode).children
+	nodes := make([]interface{}, len(children))
+	for i, child := range children {
+		nodes[i] = child
+	}
+	return nodes
+}
+
+var testTree = &treeNode{name: "root", children: []*treeNode{
+	{name: "a", children: []*treeNode{{name: "a1"}, {name: "a2"}}},
+	{name: "b", children: []*treeNode{{name: "b1"}}},
+}}
+
+func ExampleWalkTree() {
+	WalkTree(testTree, treeChildren, func(node interface{}, depth int) bool {
+		fmt.Println(strings.Repeat("  ", depth) + node.(*treeNode).name)
+		return true
+	})
+
+	// Output:
+	// root
+	//   a
+	//     a1
+	//     a2
+	//   b
+	//     b1
+}
+
+func ExampleWalkTreeBFS() {
+	var names []string
+	WalkTreeBFS(testTree, treeChildren, func(node interface{}, depth int) bool {
+		names = append(names, fmt.Sprintf("%s@%d", node.(*treeNode).name, depth))
+		return node.(*treeNode).name != "a1" // Stop at a1.
+	})
+	fmt.Println(names)
+
+	// Output:
+	// [root@0 a@1 b@1 a1@2]
+}
@@ -0,0 +1,66 @@
+package function
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiError is a collection of the errors.
+type MultiError []error
+
+// Error implements the interface error, which joins the messages of all
+// the errors by "; ".
+func (m MultiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns all the errors, which is used by errors.Is and errors.As.
+func (m MultiError) Unwrap() []error {
+	return m
+}
+
+// TryMap maps each element of the slice or array by fn in order, and returns
+// the mapped results.
+//
+// It returns early on the first error, which is wrapped with the index
+// of the failed element, and the results are discarded.
+//
+// If slice is not a slice or array type, it will panic.
+func TryMap(slice interface{}, fn func(interface{}) (interface{}, error)) ([]interface{}, error) {
+	values := interfaces(slice)
+	results := make([]interface{}, len(values))
+	for i, v := range values {
+		r, err := fn(v)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+
+// TryMapCollect is the same as TryMap, but maps all the elements and returns
+// the errors of all the failed ones as MultiError, each of which is wrapped
+// with the index of the element. The result of the failed element is nil.
+func TryMapCollect(slice interface{}, fn func(interface{}) (interface{}, error)) ([]interface{}, error) {
+	var errs MultiError
+	values := interfaces(slice)
+	results := make([]interface{}, len(values))
+	for i, v := range values {
+		r, err := fn(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("element %d: %w", i, err))
+			continue
+		}
+		results[i] = r
+	}
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
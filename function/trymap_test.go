@@ -0,0 +1,59 @@
+package function
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func atoi(v interface{}) (interface{}, error) {
+	return strconv.Atoi(v.(string))
+}
+
+func TestTryMap(t *testing.T) {
+	results, err := TryMap([]string{"1", "2", "3"}, atoi)
+	if err != nil {
+		t.Fatal(err)
+	} else if expected := []interface{}{1, 2, 3}; !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected %v, but got %v", expected, results)
+	}
+
+	var calls int
+	results, err = TryMap([]string{"1", "x", "y"}, func(v interface{}) (interface{}, error) {
+		calls++
+		return atoi(v)
+	})
+	if err == nil || results != nil {
+		t.Fatalf("expected an error, but got %v", results)
+	} else if calls != 2 {
+		t.Errorf("expected to stop at the first error, but called %d times", calls)
+	} else if msg := err.Error(); msg != `element 1: strconv.Atoi: parsing "x": invalid syntax` {
+		t.Errorf("unexpected error: %s", msg)
+	}
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("expected the wrapped *strconv.NumError, but got %T", err)
+	}
+}
+
+func TestTryMapCollect(t *testing.T) {
+	results, err := TryMapCollect([]string{"x", "2", "y"}, atoi)
+	if expected := []interface{}{nil, 2, nil}; !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected %v, but got %v", expected, results)
+	}
+
+	var errs MultiError
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 errors, but got %v", err)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || numErr.Num != "x" {
+		t.Errorf("unexpected the wrapped error: %v", numErr)
+	}
+
+	if _, err = TryMapCollect([]string{"1"}, atoi); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
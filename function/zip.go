@@ -0,0 +1,32 @@
+package function
+
+// Pair is a pair of the values.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs the elements of a and b by the index, which is truncated
+// to the shorter length.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	_len := len(a)
+	if len(b) < _len {
+		_len = len(b)
+	}
+
+	pairs := make([]Pair[A, B], _len)
+	for i := 0; i < _len; i++ {
+		pairs[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return pairs
+}
+
+// Unzip splits the pairs into two slices, which is the reverse of Zip.
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a := make([]A, len(pairs))
+	b := make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
@@ -0,0 +1,32 @@
+package function
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestZip(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	ages := []int{1, 2, 3}
+
+	pairs := Zip(names, ages)
+	expected := []Pair[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("expected %v, but got %v", expected, pairs)
+	}
+
+	_names, _ages := Unzip(pairs)
+	if !reflect.DeepEqual(_names, names) || !reflect.DeepEqual(_ages, ages) {
+		t.Errorf("unexpected unzip: %v, %v", _names, _ages)
+	}
+
+	if pairs = Zip(names, ages[:1]); len(pairs) != 1 || pairs[0] != expected[0] {
+		t.Errorf("unexpected pairs: %v", pairs)
+	}
+	if pairs = Zip(names[:2], ages); len(pairs) != 2 {
+		t.Errorf("unexpected pairs: %v", pairs)
+	}
+	if pairs = Zip([]string(nil), ages); len(pairs) != 0 {
+		t.Errorf("unexpected pairs: %v", pairs)
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SetArchiveDir sets the archive directory, into which each new backup is
+// hardlinked with its dated name on rollover, while the backup itself stays
+// in place until it's removed by the backup count. So the pipeline watching
+// the archive directory gets every backup without racing the pruning.
+//
+// If the hardlink fails, such as the directory is on another device,
+// the backup is copied instead. The directory is created if not exist.
+// The failure to archive is reported by the error callback, see
+// SetErrorCallback, rather than failing the write.
+//
+// If dir is empty, cancel it.
+func (t *TimedRotatingFile) SetArchiveDir(dir string) {
+	if dir != "" {
+		dir = absFilename(dir)
+	}
+
+	t.Lock()
+	t.archiveDir = dir
+	t.Unlock()
+}
+
+// archive hardlinks or copies the backup into the archive directory.
+func (t *TimedRotatingFile) archive(backup string) (err error) {
+	if t.archiveDir == "" || !t.isFile(backup) {
+		return
+	}
+
+	if err = t.fs.MkdirAll(t.archiveDir, os.ModePerm); err != nil {
+		return
+	}
+
+	dst := filepath.Join(t.archiveDir, filepath.Base(backup))
+	if t.exist(dst) {
+		if err = t.fs.Remove(dst); err != nil {
+			return
+		}
+	}
+
+	if t.fs == OSFS && os.Link(backup, dst) == nil {
+		return
+	}
+	return copyFile(t.fs, backup, dst)
+}
+
+// copyFile copies the file src into dst, which must not exist.
+func copyFile(fs FileSystem, src, dst string) (err error) {
+	sf, err := fs.Open(src)
+	if err != nil {
+		return
+	}
+	defer sf.Close()
+
+	df, err := fs.OpenAppend(dst, filePerm)
+	if err != nil {
+		return
+	}
+
+	if _, err = io.Copy(df, sf); err != nil {
+		df.Close()
+		fs.Remove(dst)
+		return
+	}
+	return df.Close()
+}
+
+// SetColdArchiveDir sets the cold archive directory, into which the backups
+// evicted by the backup count are moved and compressed by gzip with the suffix
+// ".gz", rather than being removed. So the hot backups uncompressed are kept
+// few, while all the history is kept in the cold archive indefinitely.
+//
+// The backups are compressed in the background, so the writes are not blocked
+// by the compression. If failing to compress the backup, it's kept in place
+// and tried again on the next rollover, and the failure is reported by
+// the error callback, see SetErrorCallback. The directory is created
+// if not exist. Close blocks until the pending compressions finish.
+//
+// If dir is empty, cancel it, and the evicted backups are removed.
+func (t *TimedRotatingFile) SetColdArchiveDir(dir string) {
+	if dir != "" {
+		dir = absFilename(dir)
+	}
+
+	t.Lock()
+	t.coldArchiveDir = dir
+	t.Unlock()
+}
+
+// evict removes the backup evicted by the backup count. If the cold archive
+// directory is set, the backup is compressed into it in the background
+// before being removed instead.
+func (t *TimedRotatingFile) evict(backup string) {
+	if t.coldArchiveDir == "" {
+		t.fs.Remove(backup)
+		t.fs.Remove(backup + indexSuffix)
+		return
+	}
+
+	if _, ok := t.evicting[backup]; ok {
+		return // Being compressed.
+	}
+	if t.evicting == nil {
+		t.evicting = make(map[string]struct{})
+	}
+	t.evicting[backup] = struct{}{}
+
+	t.evictWG.Add(1)
+	go t.compressEvicted(backup, t.coldArchiveDir)
+}
+
+// compressEvicted compresses the evicted backup into the cold archive
+// directory dir, then removes it, which is run without the lock.
+func (t *TimedRotatingFile) compressEvicted(backup, dir string) {
+	defer t.evictWG.Done()
+
+	err := t.fs.MkdirAll(dir, os.ModePerm)
+	if err == nil {
+		dst := filepath.Join(dir, filepath.Base(backup)+gzipSuffix)
+		if err = compressFile(t.fs, backup, dst); err == nil {
+			t.fs.Remove(backup)
+			t.fs.Remove(backup + indexSuffix)
+		}
+	}
+
+	t.Lock()
+	delete(t.evicting, backup)
+	t.reportError(err)
+	t.Unlock()
+}
+
+// compressFile compresses the file src into dst by gzip, which is written into
+// a temporary file firstly, so dst is either absent or complete.
+func compressFile(fs FileSystem, src, dst string) (err error) {
+	sf, err := fs.Open(src)
+	if err != nil {
+		return
+	}
+	defer sf.Close()
+
+	// Remove the temporary file left by the crash, if any.
+	tmp := dst + ".tmp"
+	if err = fs.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	df, err := fs.OpenAppend(tmp, filePerm)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			fs.Remove(tmp)
+		}
+	}()
+
+	gz := gzip.NewWriter(df)
+	if _, err = io.Copy(gz, sf); err != nil {
+		gz.Close()
+		df.Close()
+		return
+	} else if err = gz.Close(); err != nil {
+		df.Close()
+		return
+	} else if err = df.Close(); err != nil {
+		return
+	}
+	return fs.Rename(tmp, dst)
+}
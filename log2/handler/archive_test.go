@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTimedRotatingFileArchiveDir(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	archiveDir := filepath.Join(dir, "archive")
+
+	h := NewTimedRotatingFile(filename, 1)
+	defer h.Close()
+	h.SetArchiveDir(archiveDir)
+
+	h.WriteString("yesterday\n")
+	h.rotatorAt -= day
+	h.periodAt = h.periodAt.AddDate(0, 0, -1)
+	backup := h.datedFilename()
+	h.WriteString("today\n")
+
+	archived := filepath.Join(archiveDir, filepath.Base(backup))
+	afi, err := os.Stat(archived)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bfi, err := os.Stat(backup); err != nil {
+		t.Fatal(err)
+	} else if !os.SameFile(afi, bfi) {
+		t.Errorf("expected '%s' to be a hardlink of '%s'", archived, backup)
+	}
+
+	if data, _ := ioutil.ReadFile(archived); string(data) != "yesterday\n" {
+		t.Errorf("unexpected the archived data '%s'", data)
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	ioutil.WriteFile(src, []byte("data"), 0644)
+
+	if err := copyFile(OSFS, src, dst); err != nil {
+		t.Fatal(err)
+	} else if data, _ := ioutil.ReadFile(dst); string(data) != "data" {
+		t.Errorf("expected the copied data '%s', but got '%s'", "data", data)
+	}
+}
+
+func TestTimedRotatingFileColdArchiveDir(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	coldDir := filepath.Join(dir, "cold")
+
+	h := NewTimedRotatingFile(filename, 1)
+	h.SetColdArchiveDir(coldDir)
+
+	var backups []string
+	for i, line := range []string{"day 1\n", "day 2\n", "day 3\n"} {
+		h.WriteString(line)
+		h.rotatorAt -= day
+		h.periodAt = h.periodAt.AddDate(0, 0, i-3)
+		backups = append(backups, h.datedFilename())
+	}
+	h.WriteString("today\n")
+	h.Close() // Wait for the compressions.
+
+	// Only the newest backup is kept hot, and the evicted are compressed.
+	if hot, _ := h.Backups(); len(hot) != 1 || hot[0] != backups[2] {
+		t.Errorf("expected the hot backups %v, but got %v", backups[2:], hot)
+	}
+
+	for i, line := range []string{"day 1\n", "day 2\n"} {
+		cold := filepath.Join(coldDir, filepath.Base(backups[i])+gzipSuffix)
+		f, err := os.Open(cold)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Errorf("%s: %v", cold, err)
+		} else if data, _ := ioutil.ReadAll(gz); string(data) != line {
+			t.Errorf("%s: expected %q, but got %q", cold, line, data)
+		}
+		f.Close()
+	}
+
+	if files, _ := filepath.Glob(filepath.Join(coldDir, "*.tmp")); len(files) != 0 {
+		t.Errorf("unexpected the temporary files %v", files)
+	}
+}
+
+func TestTimedRotatingFileArchiveError(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+
+	// The archive directory cannot be created under the regular file.
+	notDir := filepath.Join(dir, "file")
+	ioutil.WriteFile(notDir, nil, 0644)
+
+	h := NewTimedRotatingFile(filename, 1)
+	defer h.Close()
+	h.SetArchiveDir(filepath.Join(notDir, "archive"))
+
+	var errs []error
+	h.SetErrorCallback(func(err error) { errs = append(errs, err) })
+
+	h.WriteString("yesterday\n")
+	h.rotatorAt -= day
+	h.periodAt = h.periodAt.AddDate(0, 0, -1)
+	if n, err := h.WriteString("today\n"); err != nil || n != 6 {
+		t.Errorf("expected the write not to fail, but got %d, %v", n, err)
+	}
+
+	if len(errs) != 1 {
+		t.Errorf("expected 1 archive error, but got %v", errs)
+	}
+	if data, _ := ioutil.ReadFile(filename); string(data) != "today\n" {
+		t.Errorf("expected the record to be written, but got %q", data)
+	}
+}
+
+func TestTimedRotatingFileColdArchiveError(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.log")
+	notDir := filepath.Join(dir, "file")
+	ioutil.WriteFile(notDir, nil, 0644)
+
+	h := NewTimedRotatingFile(filename, 1)
+	h.SetColdArchiveDir(filepath.Join(notDir, "cold"))
+
+	errs := make(chan error, 10)
+	h.SetErrorCallback(func(err error) { errs <- err })
+
+	for i, line := range []string{"day 1\n", "day 2\n"} {
+		h.WriteString(line)
+		h.rotatorAt -= day
+		h.periodAt = h.periodAt.AddDate(0, 0, i-2)
+	}
+	if n, err := h.WriteString("today\n"); err != nil || n != 6 {
+		t.Errorf("expected the write not to fail, but got %d, %v", n, err)
+	}
+	h.Close() // Wait for the compression.
+
+	if len(errs) != 1 {
+		t.Errorf("expected 1 eviction error, but got %d", len(errs))
+	}
+	if backups, _ := h.Backups(); len(backups) != 2 {
+		t.Errorf("expected the backup failing to compress to be kept, but got %v", backups)
+	}
+	if data, _ := ioutil.ReadFile(filename); string(data) != "today\n" {
+		t.Errorf("expected the record to be written, but got %q", data)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var errAsyncStarted = errors.New("the async handler has been started")
+
+// AsyncHandler writes the records into the underlying writer asynchronously,
+// that's, Write only puts the copy of the record into the bounded queue,
+// and the writer goroutines drain the queue into the underlying writer.
+// Write blocks when the queue is full.
+//
+// By default, there is only one writer goroutine, so the records are written
+// in the order of Write. If there are more than one writer goroutines by
+// SetWorkers, the underlying writer must be thread-safe, and the records
+// are written UNORDERED, even for those from the same goroutine.
+type AsyncHandler struct {
+	lock    sync.RWMutex
+	w       io.WriteCloser
+	queue   chan []byte
+	workers int
+	started bool
+	closed  bool
+	start   sync.Once
+	wg      sync.WaitGroup
+
+	errLock sync.Mutex
+	err     error
+}
+
+// NewAsyncHandler returns a new AsyncHandler, whose queue buffers size
+// records at most.
+func NewAsyncHandler(w io.WriteCloser, size int) *AsyncHandler {
+	if size < 0 {
+		panic(fmt.Errorf("the queue size must not be negative"))
+	}
+	return &AsyncHandler{w: w, queue: make(chan []byte, size), workers: 1}
+}
+
+// SetWorkers sets the number of the writer goroutines, which is 1 by default.
+//
+// It must be called before the first Write, or return an error.
+func (h *AsyncHandler) SetWorkers(n int) (err error) {
+	if n < 1 {
+		return fmt.Errorf("the number of the workers must be positive")
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.started {
+		return errAsyncStarted
+	}
+	h.workers = n
+	return
+}
+
+func (h *AsyncHandler) startWorkers() {
+	h.started = true
+	h.wg.Add(h.workers)
+	for i := 0; i < h.workers; i++ {
+		go h.run()
+	}
+}
+
+func (h *AsyncHandler) run() {
+	defer h.wg.Done()
+	for data := range h.queue {
+		if _, err := h.w.Write(data); err != nil {
+			h.errLock.Lock()
+			if h.err == nil {
+				h.err = err
+			}
+			h.errLock.Unlock()
+		}
+	}
+}
+
+// Write implements the interface io.Writer, which puts the copy of the data
+// into the queue, and the writer goroutines are started at the first time.
+//
+// Return ErrFileNotOpen after closed.
+func (h *AsyncHandler) Write(data []byte) (n int, err error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	if h.closed {
+		return 0, ErrFileNotOpen
+	}
+
+	h.start.Do(h.startWorkers)
+	h.queue <- append([]byte(nil), data...)
+	return len(data), nil
+}
+
+// WriteString writes the string as the record by Write.
+func (h *AsyncHandler) WriteString(data string) (n int, err error) {
+	return h.Write([]byte(data))
+}
+
+// Close stops accepting the records, waits for all the queued records to be
+// written, then closes the underlying writer.
+//
+// It returns the first error of writing the records, if any.
+func (h *AsyncHandler) Close() (err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.closed {
+		return nil
+	}
+
+	h.closed = true
+	close(h.queue)
+	h.wg.Wait()
+
+	err = h.w.Close()
+	h.errLock.Lock()
+	if h.err != nil {
+		err = h.err
+	}
+	h.errLock.Unlock()
+	return
+}
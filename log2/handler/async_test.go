@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testLockedWriter is a thread-safe in-memory WriteCloser recording the lines.
+type testLockedWriter struct {
+	sync.Mutex
+	lines  []string
+	closed bool
+}
+
+func (w *testLockedWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Microsecond)
+	w.Lock()
+	w.lines = append(w.lines, string(p))
+	w.Unlock()
+	return len(p), nil
+}
+
+func (w *testLockedWriter) Close() error {
+	w.Lock()
+	w.closed = true
+	w.Unlock()
+	return nil
+}
+
+func TestAsyncHandler(t *testing.T) {
+	w := new(testLockedWriter)
+	h := NewAsyncHandler(w, 10)
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(h, "line %03d", i)
+	}
+	if err := h.SetWorkers(4); err != errAsyncStarted {
+		t.Errorf("expected errAsyncStarted, but got %v", err)
+	}
+	h.Close()
+
+	for i, line := range w.lines {
+		if expected := fmt.Sprintf("line %03d", i); line != expected {
+			t.Fatalf("expected '%s', but got '%s'", expected, line)
+		}
+	}
+	if len(w.lines) != 100 || !w.closed {
+		t.Errorf("expected 100 lines and closed, but got %d lines and %v", len(w.lines), w.closed)
+	}
+
+	if _, err := h.WriteString("after closed"); err != ErrFileNotOpen {
+		t.Errorf("expected ErrFileNotOpen, but got %v", err)
+	}
+}
+
+func TestAsyncHandlerWorkers(t *testing.T) {
+	w := new(testLockedWriter)
+	h := NewAsyncHandler(w, 10)
+	if err := h.SetWorkers(4); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				fmt.Fprintf(h, "%d-%03d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	h.Close()
+
+	if len(w.lines) != 1000 || !w.closed {
+		t.Fatalf("expected 1000 lines and closed, but got %d lines and %v", len(w.lines), w.closed)
+	}
+
+	sort.Strings(w.lines)
+	for i, line := range w.lines {
+		if expected := fmt.Sprintf("%d-%03d", i/250, i%250); line != expected {
+			t.Fatalf("expected '%s', but got '%s'", expected, line)
+		}
+	}
+}
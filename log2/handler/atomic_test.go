@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestSizedRotatingFileAtomicRecords(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 32, 3)
+	h.SetAtomicRecords(true)
+
+	big := `{"msg": "a multi-line record",` + "\n" + `"size": "exceeding the max size"}` + "\n"
+	records := []string{big, "small record\n", "another small\n", big}
+	for _, record := range records {
+		if n, err := h.WriteString(record); err != nil || n != len(record) {
+			t.Fatalf("expected %d, but got %d, %v", len(record), n, err)
+		}
+	}
+	h.Close()
+
+	// The big record is written into the empty file without rotating it.
+	expected := map[string]string{
+		filename + ".2": big,
+		filename + ".1": "small record\nanother small\n",
+		filename:        big,
+	}
+	for name, content := range expected {
+		if data, _ := ioutil.ReadFile(name); string(data) != content {
+			t.Errorf("%s: expected %q, but got %q", name, content, data)
+		}
+	}
+
+	if backups, _ := h.Backups(); len(backups) != 2 {
+		t.Errorf("expected 2 backups without the empty one, but got %v", backups)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import "io"
+
+// Chain wraps the base writer with each middleware in order, that's,
+// the first middleware wraps base, and the last one is the outermost,
+// which is returned.
+//
+// The middlewares may be BufferedWrapper, GzipWrapper(interval), etc.
+func Chain(base io.WriteCloser, middlewares ...func(io.WriteCloser) io.WriteCloser) io.WriteCloser {
+	w := base
+	for _, middleware := range middlewares {
+		w = middleware(w)
+	}
+	return w
+}
+
+// BufferedWrapper is the middleware to wrap w by NewWriteCloser with
+// the buffer.
+func BufferedWrapper(w io.WriteCloser) io.WriteCloser {
+	return NewWriteCloser(w)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestChain(t *testing.T) {
+	base := &testKeyWriter{}
+	w := Chain(base, GzipWrapper(time.Hour), BufferedWrapper)
+	if _, ok := w.(*WriteCloser); !ok {
+		t.Errorf("expected the outermost to be the last middleware, but got %T", w)
+	}
+
+	w.Write([]byte("hello "))
+	w.Write([]byte("world\n"))
+	if base.Len() != 0 {
+		t.Errorf("expected the data to be buffered, but got %d bytes", base.Len())
+	}
+
+	w.Close()
+	if !base.closed {
+		t.Error("expected the base to be closed")
+	}
+
+	r, err := gzip.NewReader(&base.Buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadAll(r); err != nil {
+		t.Error(err)
+	} else if string(data) != "hello world\n" {
+		t.Errorf("unexpected the data: %q", data)
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var cronRE = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}-\d{2}(\.\w+)?$`)
+
+// cronLayout is the time layout of the suffix of the backups rotated
+// by CronRotatingFile, such as "filename.2006-01-02T15-04".
+const cronLayout = "2006-01-02T15-04"
+
+// CronRotatingFile is a file handler rotating on a cron-like schedule,
+// such as "every day at 00:30" or "every Sunday", which is the same as
+// TimedRotatingFile, including the dated backups and their deletion,
+// but the rollover time is the next match of the schedule.
+//
+// The backup is named by the time when the file was opened in the minute,
+// like "filename.2006-01-02T15-04".
+type CronRotatingFile struct {
+	*TimedRotatingFile
+}
+
+// NewCronRotatingFile creates a new CronRotatingFile, which rotates the file
+// when the local time matches spec, and keeps count backups at most.
+//
+// spec is the minimal subset of the cron, which consists of three fields
+// separated by the spaces, that's, "minute hour day-of-week". Each field
+// is "*" or a comma-separated list of the numbers, the ranges like "1-5"
+// and the steps like "*/15" or "0-30/10". The minute is in [0, 59], the hour
+// is in [0, 23], and the day of week is in [0, 7], where both 0 and 7 are
+// Sunday. For example,
+//
+//	"30 0 *"   // Every day at 00:30.
+//	"0 0 0"    // Every Sunday at 00:00.
+//	"0 */6 *"  // Every 6 hours.
+//
+// If the spec is invalid or failed to open the file, it will panic.
+func NewCronRotatingFile(filename, spec string, count int) *CronRotatingFile {
+	cron, err := parseCronSpec(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	t := &TimedRotatingFile{
+		filename:    absFilename(filename),
+		extRE:       cronRE,
+		layout:      cronLayout,
+		backupCount: count,
+		next:        cron.next,
+		syncDir:     fsyncDir,
+		fs:          OSFS,
+	}
+	t.reComputeRollover()
+	if err = t.open(); err != nil {
+		panic(err)
+	}
+	return &CronRotatingFile{TimedRotatingFile: t}
+}
+
+// cronSpec is the set of the matched minutes, hours and days of week,
+// each of which is a bit set.
+type cronSpec struct {
+	minutes  uint64
+	hours    uint64
+	weekdays uint64
+}
+
+func parseCronSpec(spec string) (c cronSpec, err error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 3 {
+		return c, fmt.Errorf("the cron spec '%s' must have 3 fields", spec)
+	}
+
+	if c.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return
+	}
+	if c.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return
+	}
+	if c.weekdays, err = parseCronField(fields[2], 0, 7); err != nil {
+		return
+	}
+
+	// Both 0 and 7 are Sunday.
+	if c.weekdays&(1<<7) != 0 {
+		c.weekdays |= 1
+	}
+	return
+}
+
+func parseCronField(field string, min, max int) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		start, end, step := min, max, 1
+
+		expr := part
+		if i := strings.IndexByte(part, '/'); i > -1 {
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid cron step in '%s'", part)
+			}
+			expr = part[:i]
+		}
+
+		if expr != "*" {
+			if i := strings.IndexByte(expr, '-'); i > -1 {
+				start, err = strconv.Atoi(expr[:i])
+				if err == nil {
+					end, err = strconv.Atoi(expr[i+1:])
+				}
+			} else if start, err = strconv.Atoi(expr); err == nil && expr == part {
+				end = start
+			}
+
+			if err != nil || start < min || end > max || start > end {
+				return 0, fmt.Errorf("invalid cron field '%s' out of [%d, %d]", part, min, max)
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+// match reports whether the local time t matches the spec in the minute.
+func (c cronSpec) match(t time.Time) bool {
+	return c.minutes&(1<<uint(t.Minute())) != 0 &&
+		c.hours&(1<<uint(t.Hour())) != 0 &&
+		c.weekdays&(1<<uint(t.Weekday())) != 0
+}
+
+// next returns the first time matching the spec after t in the minute,
+// which is searched minute by minute within a week.
+func (c cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for end := t.AddDate(0, 0, 8); t.Before(end); t = t.Add(time.Minute) {
+		if c.match(t) {
+			return t
+		}
+	}
+	return t // Unreachable for the valid spec.
+}
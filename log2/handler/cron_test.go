@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCronSpec(t *testing.T) {
+	for _, spec := range []string{"", "* *", "60 * *", "* 24 *", "* * 8", "*/0 * *", "5-1 * *", "a * *"} {
+		if _, err := parseCronSpec(spec); err == nil {
+			t.Errorf("expected an error for the spec '%s'", spec)
+		}
+	}
+
+	c, err := parseCronSpec("0,30 */6 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := cronSpec{
+		minutes:  1<<0 | 1<<30,
+		hours:    1<<0 | 1<<6 | 1<<12 | 1<<18,
+		weekdays: 1<<1 | 1<<2 | 1<<3 | 1<<4 | 1<<5,
+	}
+	if c != expected {
+		t.Errorf("expected %+v, but got %+v", expected, c)
+	}
+
+	if c, _ = parseCronSpec("0 0 7"); c.weekdays&1 == 0 {
+		t.Error("expected 7 to be Sunday")
+	}
+}
+
+func TestCronSpecNext(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2021, 3, 10, 12, 15, 30, 0, loc) // Wednesday
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"30 0 *", time.Date(2021, 3, 11, 0, 30, 0, 0, loc)},
+		{"0 0 0", time.Date(2021, 3, 14, 0, 0, 0, 0, loc)},
+		{"*/20 * *", time.Date(2021, 3, 10, 12, 20, 0, 0, loc)},
+		{"15 12 3", time.Date(2021, 3, 17, 12, 15, 0, 0, loc)},
+	}
+
+	for _, c := range cases {
+		spec, err := parseCronSpec(c.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next := spec.next(now); !next.Equal(c.next) {
+			t.Errorf("%s: expected %s, but got %s", c.spec, c.next, next)
+		}
+	}
+}
+
+func TestCronRotatingFile(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	now := time.Date(2021, 3, 10, 0, 10, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewCronRotatingFile(filename, "30 0 *", 1)
+	defer h.Close()
+
+	h.WriteString("first\n")
+	now = time.Date(2021, 3, 10, 0, 29, 0, 0, time.UTC)
+	h.WriteString("first\n")
+	if backups, _ := h.Backups(); len(backups) != 0 {
+		t.Fatalf("unexpected the backups: %v", backups)
+	}
+
+	now = time.Date(2021, 3, 10, 0, 30, 0, 0, time.UTC)
+	h.WriteString("second\n")
+	now = time.Date(2021, 3, 11, 0, 31, 0, 0, time.UTC)
+	h.WriteString("third\n")
+
+	backups, _ := h.Backups()
+	if expected := []string{filename + ".2021-03-10T00-30"}; !reflect.DeepEqual(backups, expected) {
+		t.Errorf("expected the backups %v, but got %v", expected, backups)
+	}
+}
@@ -0,0 +1,27 @@
+package handler
+
+// SetDurableRename controls whether to fsync the directory of the log file
+// after renaming the file on rollover, which is false by default.
+//
+// On some filesystems, such as ext4 with certain mount options, the rename
+// is not durable until the parent directory is fsynced, so the rename may be
+// lost if the system crashes right after the rollover. It's ignored on
+// the platforms not supporting fsyncing the directory, such as Windows,
+// or if the log file is not on OSFS.
+//
+// If failing to fsync the directory, the file is still reopened and the error
+// is reported by the callback set by SetErrorCallback.
+func (t *TimedRotatingFile) SetDurableRename(durable bool) {
+	t.Lock()
+	t.durable = durable
+	t.Unlock()
+}
+
+// SetDurableRename is the same as TimedRotatingFile.SetDurableRename.
+//
+// It's ignored if the log file is not on OSFS.
+func (r *SizedRotatingFile) SetDurableRename(durable bool) {
+	r.Lock()
+	r.durable = durable
+	r.Unlock()
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package handler
+
+// fsyncDir does nothing, because the directory cannot be fsynced on Windows,
+// where the rename is durable by the filesystem.
+func fsyncDir(dir string) error {
+	return nil
+}
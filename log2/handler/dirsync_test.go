@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFsyncDir(t *testing.T) {
+	if err := fsyncDir(t.TempDir()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSetDurableRename(t *testing.T) {
+	dir := t.TempDir()
+	var synced []string
+	syncDir := func(dir string) error {
+		synced = append(synced, dir)
+		return fsyncDir(dir)
+	}
+
+	timed := NewTimedRotatingFile(filepath.Join(dir, "timed.log"), 1)
+	defer timed.Close()
+	timed.syncDir = syncDir
+	timed.SetDurableRename(true)
+	timed.WriteString("yesterday\n")
+	timed.rotatorAt -= day
+	timed.periodAt = timed.periodAt.AddDate(0, 0, -1)
+	timed.WriteString("today\n")
+
+	sized := NewSizedRotatingFile(filepath.Join(dir, "sized.log"), 8, 1)
+	defer sized.Close()
+	sized.syncDir = syncDir
+	sized.WriteString("0123456789\n")
+	sized.SetDurableRename(false)
+	sized.WriteString("0123456789\n") // Not durable.
+	sized.SetDurableRename(true)
+	sized.WriteString("0123456789\n")
+
+	if expected := []string{dir, dir}; !reflect.DeepEqual(synced, expected) {
+		t.Errorf("expected the synced directories %v, but got %v", expected, synced)
+	}
+
+	memfs := NewSizedRotatingFileFS(NewMemFS(), "/mem/test.log", 8, 1)
+	memfs.syncDir = syncDir
+	memfs.SetDurableRename(true)
+	memfs.WriteString("0123456789\n")
+	memfs.WriteString("0123456789\n")
+	if len(synced) != 2 {
+		t.Errorf("expected not to sync the directory on MemFS, but got %v", synced)
+	}
+}
+
+func TestSetDurableRenameError(t *testing.T) {
+	dir := t.TempDir()
+	syncErr := errors.New("sync error")
+	syncDir := func(dir string) error { return syncErr }
+
+	var errs []error
+	onError := func(err error) { errs = append(errs, err) }
+
+	timedfile := filepath.Join(dir, "timed.log")
+	timed := NewTimedRotatingFile(timedfile, 1)
+	timed.syncDir = syncDir
+	timed.SetDurableRename(true)
+	timed.SetErrorCallback(onError)
+	timed.WriteString("yesterday\n")
+	timed.rotatorAt -= day
+	timed.periodAt = timed.periodAt.AddDate(0, 0, -1)
+	if _, err := timed.WriteString("today\n"); err != nil {
+		t.Error(err)
+	}
+	timed.Close()
+
+	sizedfile := filepath.Join(dir, "sized.log")
+	sized := NewSizedRotatingFile(sizedfile, 16, 1)
+	sized.syncDir = syncDir
+	sized.SetDurableRename(true)
+	sized.SetErrorCallback(onError)
+	sized.WriteString("0123456789\n")
+	if _, err := sized.WriteString("abcdefghij\n"); err != nil {
+		t.Error(err)
+	}
+	sized.Close()
+
+	if len(errs) != 2 || errs[0] != syncErr || errs[1] != syncErr {
+		t.Errorf("expected two sync errors, but got %v", errs)
+	}
+	if data, _ := ioutil.ReadFile(timedfile); string(data) != "today\n" {
+		t.Errorf("unexpected the content of the timed file: %q", data)
+	}
+	if data, _ := ioutil.ReadFile(sizedfile); string(data) != "abcdefghij\n" {
+		t.Errorf("unexpected the content of the sized file: %q", data)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package handler
+
+import "os"
+
+// fsyncDir fsyncs the directory dir, so that the rename in it is durable.
+func fsyncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
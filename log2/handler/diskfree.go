@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+// errDiskFreeNotSupported is returned when getting the free space of the disk
+// is not supported on the platform.
+var errDiskFreeNotSupported = errors.New("the disk free is not supported")
+
+// SetMinFreeBytes sets the minimum free bytes of the disk where the log file is.
+//
+// Before each write, if the free space is below n, the backups are removed from
+// the oldest to free the space. If it's still below n, the write returns
+// ErrDiskFull rather than writing the data, so the log doesn't fill the disk.
+//
+// If n is 0, cancel it. And it's ignored on the platforms not supporting
+// getting the free space of the disk, such as Windows, or if the log file
+// is not on OSFS.
+func (r *SizedRotatingFile) SetMinFreeBytes(n int64) {
+	r.Lock()
+	r.minFree = n
+	r.Unlock()
+}
+
+func (r *SizedRotatingFile) checkDiskFree() error {
+	if r.minFree <= 0 || r.fs != OSFS {
+		return nil
+	}
+
+	dir := filepath.Dir(r.filename)
+	free, err := r.freeBytes(dir)
+	if err == errDiskFreeNotSupported {
+		return nil
+	} else if err != nil {
+		return err
+	} else if free >= r.minFree {
+		return nil
+	}
+
+	backups, err := r.listBackups()
+	if err != nil {
+		return err
+	}
+
+	for i := len(backups) - 1; i >= 0; i-- {
+		if err = r.fs.Remove(backups[i]); err != nil {
+			return err
+		}
+
+		if free, err = r.freeBytes(dir); err != nil {
+			return err
+		} else if free >= r.minFree {
+			return nil
+		}
+	}
+	return ErrDiskFull
+}
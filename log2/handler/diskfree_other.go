@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package handler
+
+func diskFree(dir string) (int64, error) {
+	return 0, errDiskFreeNotSupported
+}
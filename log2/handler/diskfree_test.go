@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSizedRotatingFileMinFreeBytes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 10, 3)
+	defer h.Close()
+
+	for i := 0; i < 4; i++ {
+		h.WriteString("0123456789")
+	}
+	h.maxSize = 1024 // Don't rotate any more.
+
+	// Pretend that each backup takes up 100 bytes of the disk.
+	h.freeBytes = func(string) (int64, error) {
+		backups, _ := h.listBackups()
+		return int64(1000 - len(backups)*100), nil
+	}
+
+	h.SetMinFreeBytes(800)
+	if _, err := h.WriteString("data"); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := h.listBackups(); len(backups) != 2 {
+		t.Errorf("expected the oldest backup to be removed, but got %v", backups)
+	}
+
+	h.SetMinFreeBytes(1001)
+	if _, err := h.WriteString("data"); err != ErrDiskFull {
+		t.Errorf("expected ErrDiskFull, but got %v", err)
+	}
+	if backups, _ := h.listBackups(); len(backups) != 0 {
+		t.Errorf("expected all the backups to be removed, but got %v", backups)
+	}
+
+	h.SetMinFreeBytes(0)
+	if _, err := h.WriteString("data"); err != nil {
+		t.Error(err)
+	}
+}
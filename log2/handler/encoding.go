@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// encoder appends the encoded rune r to dst, and returns false if r
+// cannot be encoded by the charset.
+type encoder func(dst []byte, r rune) ([]byte, bool)
+
+// codePage is the characters of the single-byte code page from 0x80 to 0xFF,
+// and 0 means undefined.
+type codePage [128]rune
+
+// encoder returns the encoder of the code page, which is the same as
+// US-ASCII from 0x00 to 0x7F.
+func (cp *codePage) encoder() encoder {
+	table := make(map[rune]byte, len(cp))
+	for i, r := range cp {
+		if r != 0 {
+			table[r] = byte(0x80 + i)
+		}
+	}
+
+	return func(dst []byte, r rune) ([]byte, bool) {
+		if r < 0x80 {
+			return append(dst, byte(r)), true
+		} else if b, ok := table[r]; ok {
+			return append(dst, b), true
+		}
+		return dst, false
+	}
+}
+
+// charsets is the supported charsets by the lowercase name or alias,
+// and the replacement rune of the charset.
+var charsets = map[string]struct {
+	encode  encoder
+	replace rune
+}{
+	"utf-8":        {encodeUTF8, utf8.RuneError},
+	"utf8":         {encodeUTF8, utf8.RuneError},
+	"us-ascii":     {encodeASCII, '?'},
+	"ascii":        {encodeASCII, '?'},
+	"iso-8859-1":   {encodeLatin1, '?'},
+	"latin1":       {encodeLatin1, '?'},
+	"iso-8859-2":   {iso88592.encoder(), '?'},
+	"latin2":       {iso88592.encoder(), '?'},
+	"iso-8859-5":   {iso88595.encoder(), '?'},
+	"iso-8859-15":  {iso885915.encoder(), '?'},
+	"latin9":       {iso885915.encoder(), '?'},
+	"windows-1250": {windows1250.encoder(), '?'},
+	"cp1250":       {windows1250.encoder(), '?'},
+	"windows-1251": {windows1251.encoder(), '?'},
+	"cp1251":       {windows1251.encoder(), '?'},
+	"windows-1252": {windows1252.encoder(), '?'},
+	"cp1252":       {windows1252.encoder(), '?'},
+	"koi8-r":       {koi8r.encoder(), '?'},
+	"ibm866":       {ibm866.encoder(), '?'},
+	"cp866":        {ibm866.encoder(), '?'},
+	"utf-16le":     {encodeUTF16LE, utf8.RuneError},
+	"utf-16be":     {encodeUTF16BE, utf8.RuneError},
+}
+
+func encodeUTF8(dst []byte, r rune) ([]byte, bool) {
+	return utf8.AppendRune(dst, r), true
+}
+
+func encodeASCII(dst []byte, r rune) ([]byte, bool) {
+	if r < 0x80 {
+		return append(dst, byte(r)), true
+	}
+	return dst, false
+}
+
+func encodeLatin1(dst []byte, r rune) ([]byte, bool) {
+	if r < 0x100 {
+		return append(dst, byte(r)), true
+	}
+	return dst, false
+}
+
+func encodeUTF16LE(dst []byte, r rune) ([]byte, bool) {
+	return appendUTF16(dst, r, false), true
+}
+
+func encodeUTF16BE(dst []byte, r rune) ([]byte, bool) {
+	return appendUTF16(dst, r, true), true
+}
+
+func appendUTF16(dst []byte, r rune, bigEndian bool) []byte {
+	units := [2]uint16{uint16(r)}
+	n := 1
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		units[0], units[1] = uint16(r1), uint16(r2)
+		n = 2
+	}
+
+	for _, c := range units[:n] {
+		if bigEndian {
+			dst = append(dst, byte(c>>8), byte(c))
+		} else {
+			dst = append(dst, byte(c), byte(c>>8))
+		}
+	}
+	return dst
+}
+
+// EncodingHandler wraps the writer, such as the rotating file handlers,
+// and transcodes the UTF-8 records into the charset before writing them,
+// for the consumers only accepting the specific code page.
+//
+// The supported charsets are case-insensitive as follows:
+//
+//   - "UTF-8", "UTF-16LE" and "UTF-16BE"
+//   - "US-ASCII"
+//   - "ISO-8859-1" or "latin1", "ISO-8859-2" or "latin2", "ISO-8859-5",
+//     and "ISO-8859-15" or "latin9"
+//   - "Windows-1250", "Windows-1251" and "Windows-1252", or "cp125x"
+//   - "KOI8-R", and "IBM866" or "cp866"
+//
+// Notice: it only depends on the standard library, so the multi-byte
+// charsets, such as GBK, Shift_JIS and EUC-KR, are not supported, which
+// need the encodings of golang.org/x/text/encoding.
+type EncodingHandler struct {
+	w       io.WriteCloser
+	charset string
+	encode  encoder
+	repl    rune
+	replace bool
+}
+
+// NewEncodingHandler returns a new EncodingHandler, which transcodes
+// the records into charset and writes them into w.
+//
+// If replace is true, the invalid UTF-8 sequence and the character not
+// supported by the charset are replaced with the replacement character,
+// that's, '?' for the single-byte charsets and U+FFFD for the others.
+// Or, Write returns an error and the record is not written.
+//
+// Return an error if the charset is not supported.
+func NewEncodingHandler(w io.WriteCloser, charset string, replace bool) (*EncodingHandler, error) {
+	cs, ok := charsets[strings.ToLower(charset)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported charset '%s'", charset)
+	}
+
+	return &EncodingHandler{
+		w:       w,
+		charset: charset,
+		encode:  cs.encode,
+		repl:    cs.replace,
+		replace: replace,
+	}, nil
+}
+
+// Write implements the interface io.Writer, which returns the length of data
+// when the transcoded record is written successfully.
+func (h *EncodingHandler) Write(data []byte) (n int, err error) {
+	buf := bufferPool.Get().(*[]byte)
+	defer func() {
+		if cap(*buf) <= maxPooledBufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	dst := (*buf)[:0]
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		ok := r != utf8.RuneError || size != 1
+		if ok {
+			dst, ok = h.encode(dst, r)
+		}
+
+		if !ok {
+			if !h.replace {
+				return 0, fmt.Errorf("cannot encode the byte at %d into %s", i, h.charset)
+			}
+			dst, _ = h.encode(dst, h.repl)
+		}
+		i += size
+	}
+	*buf = dst
+
+	if _, err = h.w.Write(dst); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// WriteString writes the string by Write.
+func (h *EncodingHandler) WriteString(data string) (n int, err error) {
+	return writeString(h.Write, data)
+}
+
+// Close closes the underlying writer.
+func (h *EncodingHandler) Close() error {
+	return h.w.Close()
+}
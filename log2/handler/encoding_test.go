@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodingHandler(t *testing.T) {
+	tests := []struct {
+		charset string
+		replace bool
+		input   string
+		output  []byte
+		failed  bool
+	}{
+		{"UTF-8", false, "héllo\n", []byte("héllo\n"), false},
+		{"utf-8", true, "a\xffb", []byte("a�b"), false},
+		{"latin1", false, "héllo", []byte{'h', 0xE9, 'l', 'l', 'o'}, false},
+		{"ISO-8859-1", false, "€", nil, true},
+		{"ISO-8859-1", true, "a€b", []byte("a?b"), false},
+		{"Windows-1252", false, "€é—", []byte{0x80, 0xE9, 0x97}, false},
+		{"cp1252", true, "a\xffb中", []byte("a?b?"), false},
+		{"ISO-8859-2", false, "Łódź", []byte{0xA3, 0xF3, 'd', 0xBC}, false},
+		{"ISO-8859-5", false, "Мир", []byte{0xBC, 0xD8, 0xE0}, false},
+		{"latin9", false, "€", []byte{0xA4}, false},
+		{"Windows-1250", false, "Šč", []byte{0x8A, 0xE8}, false},
+		{"Windows-1251", false, "Привет", []byte{0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2}, false},
+		{"KOI8-R", false, "Привет", []byte{0xF0, 0xD2, 0xC9, 0xD7, 0xC5, 0xD4}, false},
+		{"cp866", true, "Мир€", []byte{0x8C, 0xA8, 0xE0, '?'}, false},
+		{"KOI8-R", false, "é", nil, true},
+		{"US-ASCII", false, "é", nil, true},
+		{"US-ASCII", true, "é!", []byte("?!"), false},
+		{"UTF-16LE", false, "aé😀", []byte{'a', 0, 0xE9, 0, 0x3D, 0xD8, 0x00, 0xDE}, false},
+		{"UTF-16BE", false, "aé😀", []byte{0, 'a', 0, 0xE9, 0xD8, 0x3D, 0xDE, 0x00}, false},
+	}
+
+	for _, test := range tests {
+		w := &testLockedWriter{}
+		h, err := NewEncodingHandler(w, test.charset, test.replace)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := h.WriteString(test.input)
+		if test.failed {
+			if err == nil || n != 0 || len(w.lines) != 0 {
+				t.Errorf("%s: expected an error, but got %d, %v", test.charset, n, err)
+			}
+			continue
+		}
+
+		if err != nil || n != len(test.input) {
+			t.Errorf("%s: expected %d, but got %d, %v", test.charset, len(test.input), n, err)
+		} else if len(w.lines) != 1 || !bytes.Equal([]byte(w.lines[0]), test.output) {
+			t.Errorf("%s: expected %v, but got %q", test.charset, test.output, w.lines)
+		}
+	}
+
+	if _, err := NewEncodingHandler(&testLockedWriter{}, "ebcdic", true); err == nil {
+		t.Errorf("expected an error for the unsupported charset")
+	}
+}
+
+func TestEncodingHandlerRotatingFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h, err := NewEncodingHandler(NewSizedRotatingFile(filename, 1024, 1), "latin1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.WriteString("café\n")
+	h.Close()
+
+	if data, _ := ioutil.ReadFile(filename); !bytes.Equal(data, []byte("caf\xe9\n")) {
+		t.Errorf("unexpected the file content %q", data)
+	}
+}
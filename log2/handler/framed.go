@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// frameHeaderSize is the size of the big-endian length prefix of the frame.
+const frameHeaderSize = 4
+
+// MaxRecordSize is the max size of the payload of a frame, which is 64MB
+// by default. WriteRecord rejects the larger record, and ReadRecord rejects
+// the larger length prefix, such as the corrupted one, instead of allocating
+// the memory of up to 4GB for it.
+var MaxRecordSize = 64 << 20
+
+// ErrRecordTooLarge is returned when the record exceeds MaxRecordSize.
+var ErrRecordTooLarge = errors.New("The record is too large")
+
+// FramedRotatingFile is a rotating logging handler based on the size like
+// SizedRotatingFile, but it writes each record as a frame, that's, the 4-byte
+// big-endian length prefix followed by the payload, which is used by
+// the binary log.
+//
+// A frame is written as one logical unit, which never splits across
+// the rollover, so every file contains the complete frames only,
+// which is able to be read by ReadRecord.
+type FramedRotatingFile struct {
+	r *SizedRotatingFile
+}
+
+// NewFramedRotatingFile returns a new FramedRotatingFile.
+//
+// The arguments are the same as NewSizedRotatingFile, and the size contains
+// the length prefixes. If failed, it will panic.
+func NewFramedRotatingFile(filename string, size, count int) *FramedRotatingFile {
+	return &FramedRotatingFile{r: NewSizedRotatingFile(filename, size, count)}
+}
+
+// WriteRecord writes the data as a frame, which may rotate the file before
+// writing the frame if the file would exceed the max size.
+func (f *FramedRotatingFile) WriteRecord(data []byte) (err error) {
+	if len(data) > MaxRecordSize {
+		return ErrRecordTooLarge
+	}
+
+	frame := make([]byte, frameHeaderSize+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[frameHeaderSize:], data)
+
+	f.r.Lock()
+	defer f.r.Unlock()
+
+	if err = f.r.checkDiskFree(); err != nil {
+		return
+	}
+	_, err = f.r.writeData(frame)
+	return
+}
+
+// Write implements the interface io.Writer, which writes the data as a frame
+// by WriteRecord.
+func (f *FramedRotatingFile) Write(data []byte) (n int, err error) {
+	if err = f.WriteRecord(data); err != nil {
+		return
+	}
+	return len(data), nil
+}
+
+// Close closes the handler.
+func (f *FramedRotatingFile) Close() error {
+	return f.r.Close()
+}
+
+// Backups returns the paths of the backup files like SizedRotatingFile.
+func (f *FramedRotatingFile) Backups() ([]string, error) {
+	return f.r.Backups()
+}
+
+// ReadRecord reads the payload of a frame written by FramedRotatingFile
+// from r.
+//
+// It returns io.EOF if there is no frame any more, io.ErrUnexpectedEOF
+// if the frame is torn, or ErrRecordTooLarge if the length prefix exceeds
+// MaxRecordSize.
+func ReadRecord(r io.Reader) (data []byte, err error) {
+	var header [frameHeaderSize]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		return
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if uint64(size) > uint64(MaxRecordSize) {
+		return nil, ErrRecordTooLarge
+	}
+
+	data = make([]byte, size)
+	if _, err = io.ReadFull(r, data); err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
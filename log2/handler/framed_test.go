@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFramedRotatingFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.bin")
+	h := NewFramedRotatingFile(filename, 64, 100)
+	for i := 0; i < 20; i++ {
+		if err := h.WriteRecord(bytes.Repeat([]byte{byte(i)}, i%7)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Close()
+
+	backups, err := h.Backups()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(backups) < 2 {
+		t.Fatalf("expected the rotation, but got %d backups", len(backups))
+	}
+
+	var i int
+	for j := len(backups) - 1; j >= -1; j-- {
+		fn := filename
+		if j >= 0 {
+			fn = backups[j]
+		}
+
+		f, err := os.Open(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := bufio.NewReader(f)
+		for ; ; i++ {
+			data, err := ReadRecord(r)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s: %v", fn, err)
+			}
+
+			if expected := bytes.Repeat([]byte{byte(i)}, i%7); !bytes.Equal(data, expected) {
+				t.Fatalf("%s: expected the record %v, but got %v", fn, expected, data)
+			}
+		}
+		f.Close()
+	}
+
+	if i != 20 {
+		t.Errorf("expected 20 records, but got %d", i)
+	}
+}
+
+func TestReadRecordTorn(t *testing.T) {
+	if _, err := ReadRecord(bytes.NewReader([]byte{0, 0, 0, 5, 'a'})); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF, but got %v", err)
+	}
+	if _, err := ReadRecord(bytes.NewReader([]byte{0, 0})); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF, but got %v", err)
+	}
+	if data, err := ReadRecord(bytes.NewReader([]byte{0, 0, 0, 0})); err != nil || len(data) != 0 {
+		t.Errorf("expected the empty record, but got %v, %v", data, err)
+	}
+}
+
+func TestFramedRotatingFileTooLarge(t *testing.T) {
+	defer func(size int) { MaxRecordSize = size }(MaxRecordSize)
+	MaxRecordSize = 8
+
+	filename := filepath.Join(t.TempDir(), "test.bin")
+	h := NewFramedRotatingFile(filename, 64, 1)
+	defer h.Close()
+
+	if err := h.WriteRecord(make([]byte, 9)); err != ErrRecordTooLarge {
+		t.Errorf("expected ErrRecordTooLarge, but got %v", err)
+	}
+	if err := h.WriteRecord(make([]byte, 8)); err != nil {
+		t.Error(err)
+	} else if h.r.empty {
+		t.Error("expected the file not to be empty after writing the record")
+	}
+
+	if _, err := ReadRecord(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF})); err != ErrRecordTooLarge {
+		t.Errorf("expected ErrRecordTooLarge, but got %v", err)
+	}
+}
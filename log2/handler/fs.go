@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xgfone/go-tools/file"
+)
+
+// File is the file opened by FileSystem to be written.
+type File interface {
+	io.WriteCloser
+	Stat() (os.FileInfo, error)
+}
+
+// ReadableFile is the file opened by FileSystem to be read.
+type ReadableFile interface {
+	io.ReadCloser
+	io.ReaderAt
+}
+
+// FileSystem is the minimal filesystem used by SizedRotatingFile and
+// TimedRotatingFile to open, rotate, archive and list the log files,
+// so the rollover is able to be tested without the real files by
+// the in-memory MemFS.
+//
+// The errors for the nonexistent files should satisfy os.IsNotExist.
+type FileSystem interface {
+	// OpenAppend opens the file to append like file.OpenAppend.
+	OpenAppend(name string, perm os.FileMode) (File, error)
+	// Open opens the file to read like os.Open.
+	Open(name string) (ReadableFile, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	Truncate(name string, size int64) error
+	Stat(name string) (os.FileInfo, error)
+	// Lstat is the same as Stat, but doesn't follow the symbolic link.
+	Lstat(name string) (os.FileInfo, error)
+	Symlink(oldname, newname string) error
+	// ReadDir returns the names of the files in the directory.
+	ReadDir(dirname string) ([]string, error)
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// OSFS is the FileSystem of the operating system, which is the default.
+var OSFS FileSystem = osFS{}
+
+type osFS struct{}
+
+func (osFS) OpenAppend(name string, perm os.FileMode) (File, error) {
+	f, err := file.OpenAppend(name, perm)
+	if err != nil {
+		return nil, err // Avoid the non-nil File with the nil *os.File.
+	}
+	return f, nil
+}
+
+func (osFS) Open(name string) (ReadableFile, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (osFS) Rename(oldpath, newpath string) error     { return os.Rename(oldpath, newpath) }
+func (osFS) Remove(name string) error                 { return os.Remove(name) }
+func (osFS) Truncate(name string, size int64) error   { return os.Truncate(name, size) }
+func (osFS) Stat(name string) (os.FileInfo, error)    { return os.Stat(name) }
+func (osFS) Lstat(name string) (os.FileInfo, error)   { return os.Lstat(name) }
+func (osFS) Symlink(oldname, newname string) error    { return os.Symlink(oldname, newname) }
+func (osFS) ReadDir(dirname string) ([]string, error) { return file.ListDir2(dirname) }
+func (osFS) MkdirAll(path string, perm os.FileMode) error {
+	return file.EnsureDir(path, perm)
+}
+
+// MemFS is an in-memory FileSystem, which is used to test the rollover
+// deterministically. It's thread-safe, and all the paths are cleaned.
+//
+// The symbolic link is resolved only once, that's, it must point to
+// the file, not another symbolic link.
+type MemFS struct {
+	lock  sync.Mutex
+	files map[string]*memFile
+	dirs  map[string]bool
+	links map[string]string
+}
+
+// NewMemFS returns a new empty MemFS.
+func NewMemFS() *MemFS {
+	return &MemFS{
+		files: make(map[string]*memFile),
+		dirs:  make(map[string]bool),
+		links: make(map[string]string),
+	}
+}
+
+// resolve returns the cleaned path, which follows the symbolic link.
+func (fs *MemFS) resolve(name string) string {
+	name = filepath.Clean(name)
+	if target, ok := fs.links[name]; ok {
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(name), target)
+		}
+		return filepath.Clean(target)
+	}
+	return name
+}
+
+type memFile struct {
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// ReadFile returns the copy of the content of the file.
+func (fs *MemFS) ReadFile(name string) ([]byte, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	f, ok := fs.files[fs.resolve(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+// Open implements the interface FileSystem, which reads the snapshot
+// of the file when opened.
+func (fs *MemFS) Open(name string) (ReadableFile, error) {
+	data, err := fs.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return memReader{bytes.NewReader(data)}, nil
+}
+
+// OpenAppend implements the interface FileSystem.
+func (fs *MemFS) OpenAppend(name string, perm os.FileMode) (File, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	name = fs.resolve(name)
+	if fs.dirs[name] {
+		return nil, &os.PathError{Op: "open", Path: name, Err: file.ErrNotDir}
+	}
+
+	f, ok := fs.files[name]
+	if !ok {
+		f = &memFile{mode: perm, modTime: time.Now()}
+		fs.files[name] = f
+	}
+	return &memHandle{fs: fs, name: name, file: f}, nil
+}
+
+// Rename implements the interface FileSystem.
+func (fs *MemFS) Rename(oldpath, newpath string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	if target, ok := fs.links[oldpath]; ok {
+		delete(fs.links, oldpath)
+		delete(fs.files, newpath)
+		fs.links[newpath] = target
+		return nil
+	}
+
+	f, ok := fs.files[oldpath]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: os.ErrNotExist}
+	}
+	delete(fs.files, oldpath)
+	delete(fs.links, newpath)
+	fs.files[newpath] = f
+	return nil
+}
+
+// Remove implements the interface FileSystem.
+func (fs *MemFS) Remove(name string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	name = filepath.Clean(name)
+	if _, ok := fs.links[name]; ok {
+		delete(fs.links, name)
+		return nil
+	} else if _, ok := fs.files[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(fs.files, name)
+	return nil
+}
+
+// Truncate implements the interface FileSystem.
+func (fs *MemFS) Truncate(name string, size int64) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	f, ok := fs.files[fs.resolve(name)]
+	if !ok {
+		return &os.PathError{Op: "truncate", Path: name, Err: os.ErrNotExist}
+	}
+
+	if size <= int64(len(f.data)) {
+		f.data = f.data[:size]
+	} else {
+		f.data = append(f.data, make([]byte, size-int64(len(f.data)))...)
+	}
+	f.modTime = time.Now()
+	return nil
+}
+
+// Stat implements the interface FileSystem.
+func (fs *MemFS) Stat(name string) (os.FileInfo, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	return fs.stat(fs.resolve(name))
+}
+
+// Lstat implements the interface FileSystem.
+func (fs *MemFS) Lstat(name string) (os.FileInfo, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	name = filepath.Clean(name)
+	if _, ok := fs.links[name]; ok {
+		return memFileInfo{name: filepath.Base(name), mode: os.ModeSymlink | 0777}, nil
+	}
+	return fs.stat(name)
+}
+
+// Symlink implements the interface FileSystem.
+func (fs *MemFS) Symlink(oldname, newname string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	newname = filepath.Clean(newname)
+	if _, ok := fs.files[newname]; ok {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrExist}
+	} else if _, ok := fs.links[newname]; ok {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+	fs.links[newname] = oldname
+	return nil
+}
+
+func (fs *MemFS) stat(name string) (os.FileInfo, error) {
+	if f, ok := fs.files[name]; ok {
+		return memFileInfo{name: filepath.Base(name), size: int64(len(f.data)),
+			mode: f.mode, modTime: f.modTime}, nil
+	} else if fs.dirs[name] {
+		return memFileInfo{name: filepath.Base(name), mode: os.ModeDir | 0755}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// ReadDir implements the interface FileSystem, which returns the sorted
+// names of the files in the directory.
+func (fs *MemFS) ReadDir(dirname string) ([]string, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	dirname = filepath.Clean(dirname)
+	names := make([]string, 0, len(fs.files)+len(fs.links))
+	for name := range fs.files {
+		if filepath.Dir(name) == dirname {
+			names = append(names, filepath.Base(name))
+		}
+	}
+	for name := range fs.links {
+		if filepath.Dir(name) == dirname {
+			names = append(names, filepath.Base(name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// MkdirAll implements the interface FileSystem.
+func (fs *MemFS) MkdirAll(path string, perm os.FileMode) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	for path = filepath.Clean(path); !fs.dirs[path]; path = filepath.Dir(path) {
+		if _, ok := fs.files[path]; ok {
+			return file.ErrNotDir
+		}
+		fs.dirs[path] = true
+	}
+	return nil
+}
+
+// memHandle is the opened file of MemFS, which appends the data.
+type memHandle struct {
+	fs     *MemFS
+	name   string
+	file   *memFile
+	closed bool
+}
+
+func (h *memHandle) Write(p []byte) (int, error) {
+	h.fs.lock.Lock()
+	defer h.fs.lock.Unlock()
+	if h.closed {
+		return 0, os.ErrClosed
+	}
+
+	h.file.data = append(h.file.data, p...)
+	h.file.modTime = time.Now()
+	return len(p), nil
+}
+
+func (h *memHandle) Close() error {
+	h.fs.lock.Lock()
+	defer h.fs.lock.Unlock()
+	if h.closed {
+		return os.ErrClosed
+	}
+	h.closed = true
+	return nil
+}
+
+func (h *memHandle) Stat() (os.FileInfo, error) {
+	h.fs.lock.Lock()
+	defer h.fs.lock.Unlock()
+	return memFileInfo{name: filepath.Base(h.name), size: int64(len(h.file.data)),
+		mode: h.file.mode, modTime: h.file.modTime}, nil
+}
+
+// memReader is the file of MemFS opened to be read.
+type memReader struct{ *bytes.Reader }
+
+func (memReader) Close() error { return nil }
+
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi memFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi memFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi memFileInfo) Sys() interface{}   { return nil }
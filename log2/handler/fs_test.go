@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSizedRotatingFileMemFS(t *testing.T) {
+	fs := NewMemFS()
+	filename := "/var/log/app/test.log"
+	h := NewSizedRotatingFileFS(fs, filename, 10, 2)
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "ffff\n", "gggg\n"} {
+		if _, err := h.WriteString(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Close()
+
+	names, _ := fs.ReadDir("/var/log/app")
+	if expected := []string{"test.log", "test.log.1", "test.log.2"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected the files %v, but got %v", expected, names)
+	}
+
+	for fn, expected := range map[string]string{
+		filename:        "gggg\n",
+		filename + ".1": "eeee\nffff\n",
+		filename + ".2": "cccc\ndddd\n",
+	} {
+		if data, err := fs.ReadFile(fn); err != nil {
+			t.Error(err)
+		} else if string(data) != expected {
+			t.Errorf("%s: expected %q, but got %q", fn, expected, data)
+		}
+	}
+
+	if backups, _ := h.Backups(); !reflect.DeepEqual(backups, []string{filename + ".1", filename + ".2"}) {
+		t.Errorf("unexpected the backups: %v", backups)
+	}
+	if _, err := os.Stat("/var/log/app/test.log"); err == nil {
+		t.Error("unexpected the real file")
+	}
+}
+
+func TestSizedRotatingFileMemFSMinFree(t *testing.T) {
+	h := NewSizedRotatingFileFS(NewMemFS(), "/var/log/app/test.log", 10, 2)
+	defer h.Close()
+
+	// The free space of the disk is not probed on MemFS.
+	h.SetMinFreeBytes(1 << 62)
+	if _, err := h.WriteString("aaaa\n"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTimedRotatingFileMemFS(t *testing.T) {
+	fs := NewMemFS()
+	filename := "/var/log/app/test.log"
+	h := NewTimedRotatingFileFS(fs, filename, 1)
+	h.SetArchiveDir("/var/log/archive")
+	h.SetColdArchiveDir("/var/log/cold")
+	h.SetTimeIndex(time.Nanosecond)
+
+	var backups []string
+	for i, line := range []string{"day 1\n", "day 2\n"} {
+		h.WriteString(line)
+		h.rotatorAt -= day
+		h.periodAt = h.periodAt.AddDate(0, 0, i-2)
+		backups = append(backups, h.datedFilename())
+	}
+	h.WriteString("today\n")
+
+	// Switch the active file to the dated one linked by the filename.
+	if err := h.SetActiveDated(true); err != nil {
+		t.Fatal(err)
+	}
+	active := h.datedFilename()
+	h.Close()
+
+	if data, _ := fs.ReadFile(filename); string(data) != "today\n" {
+		t.Errorf("expected the link to the active file, but got %q", data)
+	}
+	if fi, err := fs.Lstat(filename); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected the symbolic link, but got %v", err)
+	}
+	if hot, _ := h.Backups(); !reflect.DeepEqual(hot, backups[1:]) {
+		t.Errorf("expected the hot backups %v, but got %v", backups[1:], hot)
+	}
+	if _, err := fs.Stat(active + indexSuffix); err != nil {
+		t.Errorf("expected the time index of the active file: %v", err)
+	}
+	if _, err := fs.Stat(backups[1] + indexSuffix); err != nil {
+		t.Errorf("expected the time index of the backup: %v", err)
+	}
+
+	for i, line := range []string{"day 1\n", "day 2\n"} {
+		name := filepath.Join("/var/log/archive", filepath.Base(backups[i]))
+		if data, _ := fs.ReadFile(name); string(data) != line {
+			t.Errorf("%s: expected %q, but got %q", name, line, data)
+		}
+	}
+
+	cold := filepath.Join("/var/log/cold", filepath.Base(backups[0])+gzipSuffix)
+	if data, err := fs.ReadFile(cold); err != nil {
+		t.Error(err)
+	} else if gz, err := gzip.NewReader(bytes.NewReader(data)); err != nil {
+		t.Errorf("%s: %v", cold, err)
+	} else if data, _ = ioutil.ReadAll(gz); string(data) != "day 1\n" {
+		t.Errorf("%s: expected %q, but got %q", cold, "day 1\n", data)
+	}
+
+	if _, err := os.Stat("/var/log/app"); err == nil {
+		t.Error("unexpected the real directory")
+	}
+}
+
+func TestMemFS(t *testing.T) {
+	fs := NewMemFS()
+	if _, err := fs.Stat("/a"); !os.IsNotExist(err) {
+		t.Errorf("expected the nonexistent error, but got %v", err)
+	}
+	if err := fs.Remove("/a"); !os.IsNotExist(err) {
+		t.Errorf("expected the nonexistent error, but got %v", err)
+	}
+
+	f, _ := fs.OpenAppend("/dir/a", 0600)
+	f.Write([]byte("abc"))
+	if info, _ := f.Stat(); info.Size() != 3 || info.Mode() != 0600 {
+		t.Errorf("unexpected the file info: %d, %s", info.Size(), info.Mode())
+	}
+	f.Close()
+	if _, err := f.Write([]byte("d")); err == nil {
+		t.Error("expected an error after closed")
+	}
+
+	if err := fs.Rename("/dir/a", "/dir/b"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fs.ReadFile("/dir/b"); string(data) != "abc" {
+		t.Errorf("unexpected the content: %q", data)
+	}
+	if err := fs.MkdirAll("/dir/b/c", 0755); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("expected the error of the file as the directory, but got %v", err)
+	}
+
+	if err := fs.Symlink("b", "/dir/link"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fs.Lstat("/dir/link"); info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected the symbolic link, but got %s", info.Mode())
+	}
+	if info, _ := fs.Stat("/dir/link"); info.Size() != 3 {
+		t.Errorf("expected to follow the symbolic link, but got %d", info.Size())
+	}
+	if names, _ := fs.ReadDir("/dir"); !reflect.DeepEqual(names, []string{"b", "link"}) {
+		t.Errorf("unexpected the files: %v", names)
+	}
+
+	if err := fs.Truncate("/dir/link", 2); err != nil {
+		t.Fatal(err)
+	}
+	r, err := fs.Open("/dir/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2)
+	if n, err := r.ReadAt(buf, 1); n != 1 || err != io.EOF || buf[0] != 'b' {
+		t.Errorf("unexpected the read: %d, %v, %q", n, err, buf[:n])
+	}
+	r.Close()
+
+	if err := fs.Remove("/dir/link"); err != nil {
+		t.Fatal(err)
+	} else if _, err := fs.Stat("/dir/b"); err != nil {
+		t.Errorf("expected the target to be kept, but got %v", err)
+	}
+}
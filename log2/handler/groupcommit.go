@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Syncer is the writer able to commit the written data to the stable storage,
+// such as *os.File.
+type Syncer interface {
+	io.WriteCloser
+	Sync() error
+}
+
+// GroupCommitHandler is the group commit of the writes, that's, it writes
+// the data into the underlying file at once, but fsyncs the file at most once
+// every interval, or once the size of the unsynced data reaches the threshold,
+// which trades a little latency of the durability for the higher throughput
+// than fsyncing every write.
+//
+// Write returns after the data is in the OS buffer, and each write is
+// assigned a sequence number by WriteSeq. After each group fsync, the sync
+// callback is called with the sequence number of the last synced write,
+// so the writes whose sequence numbers are not greater than it are durable.
+type GroupCommitHandler struct {
+	lock    sync.Mutex
+	w       Syncer
+	seq     uint64
+	synced  uint64
+	pending int
+	size    int
+	closed  bool
+	onSync  func(synced uint64, err error)
+
+	kick chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewGroupCommitHandler returns a new GroupCommitHandler, which fsyncs w
+// every interval, or once the unsynced data reaches size bytes if size
+// is positive.
+//
+// onSync is called after each fsync in the background goroutine, which may
+// be nil. If the fsync fails, it's called with the error, and the writes are
+// retried to be synced by the next fsync.
+func NewGroupCommitHandler(w Syncer, interval time.Duration, size int,
+	onSync func(synced uint64, err error)) *GroupCommitHandler {
+	h := &GroupCommitHandler{
+		w:      w,
+		size:   size,
+		onSync: onSync,
+		kick:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go h.loop(interval)
+	return h
+}
+
+func (h *GroupCommitHandler) loop(interval time.Duration) {
+	defer close(h.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		case <-h.kick:
+		}
+		h.sync()
+	}
+}
+
+func (h *GroupCommitHandler) sync() (err error) {
+	h.lock.Lock()
+	seq := h.seq
+	if seq == h.synced {
+		h.lock.Unlock()
+		return
+	}
+	h.pending = 0
+	h.lock.Unlock()
+
+	// Sync outside the lock, so the writes are not blocked by the fsync.
+	if err = h.w.Sync(); err == nil {
+		h.lock.Lock()
+		h.synced = seq
+		h.lock.Unlock()
+	}
+
+	if h.onSync != nil {
+		h.onSync(seq, err)
+	}
+	return
+}
+
+// Synced returns the sequence number of the last synced write.
+func (h *GroupCommitHandler) Synced() uint64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.synced
+}
+
+// WriteSeq writes the data into the underlying file, and returns
+// the sequence number of the write, which starts from 1.
+//
+// Return ErrFileNotOpen after closed.
+func (h *GroupCommitHandler) WriteSeq(data []byte) (seq uint64, err error) {
+	_, seq, err = h.writeSeq(data)
+	return
+}
+
+// writeSeq is the same as WriteSeq, but also returns the number of the bytes
+// written into the underlying file, even if failing to write all the data.
+func (h *GroupCommitHandler) writeSeq(data []byte) (n int, seq uint64, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return 0, 0, ErrFileNotOpen
+	}
+
+	n, err = h.w.Write(data)
+	if n > 0 || err == nil {
+		h.seq++
+		seq = h.seq
+		h.pending += n
+		if h.size > 0 && h.pending >= h.size {
+			select {
+			case h.kick <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return
+}
+
+// Write implements the interface io.Writer by WriteSeq, which returns
+// the number of the bytes written into the underlying file with the error.
+func (h *GroupCommitHandler) Write(data []byte) (n int, err error) {
+	n, _, err = h.writeSeq(data)
+	return
+}
+
+// WriteString writes the string by Write.
+func (h *GroupCommitHandler) WriteString(data string) (n int, err error) {
+	return h.Write([]byte(data))
+}
+
+// Close stops the background fsync, then fsyncs the unsynced writes
+// and closes the underlying file.
+func (h *GroupCommitHandler) Close() (err error) {
+	h.lock.Lock()
+	if h.closed {
+		h.lock.Unlock()
+		return nil
+	}
+	h.closed = true
+	h.lock.Unlock()
+
+	close(h.stop)
+	<-h.done
+
+	err = h.sync()
+	if _err := h.w.Close(); err == nil {
+		err = _err
+	}
+	return
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSyncer struct {
+	testLockedWriter
+	syncs   int
+	syncErr error
+}
+
+func (s *testSyncer) Sync() error {
+	s.Lock()
+	defer s.Unlock()
+	s.syncs++
+	return s.syncErr
+}
+
+func TestGroupCommitHandler(t *testing.T) {
+	var lock sync.Mutex
+	var synceds []uint64
+	onSync := func(synced uint64, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		lock.Lock()
+		synceds = append(synceds, synced)
+		lock.Unlock()
+	}
+
+	w := new(testSyncer)
+	h := NewGroupCommitHandler(w, time.Hour, 10, onSync)
+	for i := 0; i < 3; i++ {
+		if seq, err := h.WriteSeq([]byte("abcd")); err != nil || seq != uint64(i+1) {
+			t.Fatalf("expected the sequence %d, but got %d, %v", i+1, seq, err)
+		}
+	}
+
+	// The size threshold is reached, so the group fsync is triggered.
+	for start := time.Now(); h.Synced() != 3; time.Sleep(time.Millisecond) {
+		if time.Since(start) > time.Second {
+			t.Fatalf("expected the writes to be synced, but got %d", h.Synced())
+		}
+	}
+
+	h.WriteString("tail")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Synced() != 4 || w.syncs != 2 || !w.closed {
+		t.Errorf("unexpected synced=%d, syncs=%d, closed=%v", h.Synced(), w.syncs, w.closed)
+	}
+	if expected := []uint64{3, 4}; len(synceds) != 2 || synceds[0] != 3 || synceds[1] != 4 {
+		t.Errorf("expected the callbacks %v, but got %v", expected, synceds)
+	}
+	if _, err := h.WriteString("closed"); err != ErrFileNotOpen {
+		t.Errorf("expected ErrFileNotOpen, but got %v", err)
+	}
+}
+
+func TestGroupCommitHandlerInterval(t *testing.T) {
+	w := &testSyncer{syncErr: errors.New("sync failed")}
+	errs := make(chan error, 10)
+	h := NewGroupCommitHandler(w, 5*time.Millisecond, 0, func(_ uint64, err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	h.WriteString("data")
+
+	select {
+	case err := <-errs:
+		if err != w.syncErr {
+			t.Errorf("expected the sync error, but got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the periodic fsync")
+	}
+
+	if h.Synced() != 0 {
+		t.Errorf("expected no synced write, but got %d", h.Synced())
+	}
+	if err := h.Close(); err != w.syncErr {
+		t.Errorf("expected the sync error, but got %v", err)
+	}
+}
+
+func TestGroupCommitHandlerRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	sizedfile := filepath.Join(dir, "sized.log")
+	timedfile := filepath.Join(dir, "timed.log")
+	for filename, w := range map[string]Syncer{
+		sizedfile: NewSizedRotatingFile(sizedfile, 1024, 1),
+		timedfile: NewTimedRotatingFile(timedfile, 1),
+	} {
+		errs := make(chan error, 1)
+		h := NewGroupCommitHandler(w, time.Hour, 1, func(_ uint64, err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+		if _, err := h.WriteString("data\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Errorf("%s: %s", filename, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: expected the fsync", filename)
+		}
+		if data, _ := ioutil.ReadFile(filename); string(data) != "data\n" {
+			t.Errorf("%s: expected the synced data, but got %q", filename, data)
+		}
+		if err := h.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+type testShortSyncer struct{ testSyncer }
+
+func (s *testShortSyncer) Write(p []byte) (int, error) {
+	s.testSyncer.Write(p[:2])
+	return 2, io.ErrShortWrite
+}
+
+func TestGroupCommitHandlerShortWrite(t *testing.T) {
+	h := NewGroupCommitHandler(&testShortSyncer{}, time.Hour, 0, nil)
+	defer h.Close()
+
+	if n, err := h.WriteString("abcd"); n != 2 || err != io.ErrShortWrite {
+		t.Errorf("expected the short write of 2 bytes, but got %d, %v", n, err)
+	}
+	if seq, _ := h.WriteSeq([]byte("abcd")); seq != 2 {
+		t.Errorf("expected the partial write to be counted, but got %d", seq)
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// GzipHandler compresses the data by gzip on the fly and writes it into
+// the underlying writer.
+//
+// In order not to lose the tail-ability completely, it flushes the pending
+// compressed data periodically, so the data written before the flush is able
+// to be decompressed even if the gzip stream has not been closed.
+//
+// Combined with SizedRotatingFile by SetWrapper(GzipWrapper(interval)),
+// every file is a valid gzip file, whose gzip stream is closed when rotating
+// or closing the file. But the size for the rotation measures the compressed
+// bytes, which lag behind the written data until the gzip writer flushes.
+type GzipHandler struct {
+	sync.Mutex
+	w        io.WriteCloser
+	gz       *gzip.Writer
+	interval time.Duration
+	last     time.Time
+}
+
+// NewGzipHandler returns a new GzipHandler, which flushes the compressed
+// data into w after the write every interval. If interval is 0, it flushes
+// after every write.
+func NewGzipHandler(w io.WriteCloser, interval time.Duration) *GzipHandler {
+	return &GzipHandler{
+		w:        w,
+		gz:       gzip.NewWriter(w),
+		interval: interval,
+		last:     time.Now(),
+	}
+}
+
+// GzipWrapper returns a wrapper function, which wraps the writer by
+// NewGzipHandler with the interval.
+func GzipWrapper(interval time.Duration) func(io.WriteCloser) io.WriteCloser {
+	return func(w io.WriteCloser) io.WriteCloser {
+		return NewGzipHandler(w, interval)
+	}
+}
+
+// Write implements the interface io.Writer.
+func (g *GzipHandler) Write(data []byte) (n int, err error) {
+	g.Lock()
+	defer g.Unlock()
+
+	if n, err = g.gz.Write(data); err != nil {
+		return
+	}
+
+	if now := time.Now(); now.Sub(g.last) >= g.interval {
+		g.last = now
+		err = g.gz.Flush()
+	}
+	return
+}
+
+// WriteString writes the string.
+func (g *GzipHandler) WriteString(data string) (n int, err error) {
+	return g.Write([]byte(data))
+}
+
+// Flush flushes the pending compressed data into the underlying writer.
+func (g *GzipHandler) Flush() (err error) {
+	g.Lock()
+	g.last = time.Now()
+	err = g.gz.Flush()
+	g.Unlock()
+	return
+}
+
+// Close closes the gzip stream and the underlying writer.
+func (g *GzipHandler) Close() (err error) {
+	g.Lock()
+	defer g.Unlock()
+
+	err = g.gz.Close()
+	if _err := g.w.Close(); err == nil {
+		err = _err
+	}
+	return
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGzipHandler(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log.gz")
+	h := NewSizedRotatingFile(filename, 1024, 100)
+	if err := h.SetWrapper(GzipWrapper(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(h, "the log line %d with some random data %x\n", i, i*7919)
+	}
+	h.Close()
+
+	backups, _ := h.Backups()
+	if len(backups) == 0 {
+		t.Fatal("expected the rotation")
+	}
+
+	var lines int
+	for _, fn := range append(backups, filename) {
+		f, err := os.Open(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("%s: %s", fn, err)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s: %s", fn, err)
+		}
+		f.Close()
+		lines += bytes.Count(data, []byte("\n"))
+	}
+
+	if lines != 1000 {
+		t.Errorf("expected 1000 lines, but got %d", lines)
+	}
+}
+
+func TestGzipHandlerFlush(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 1024*1024, 1)
+	defer h.Close()
+
+	h.Write([]byte("plain\n"))
+	if err := h.SetWrapper(GzipWrapper(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(filename + ".1"); string(data) != "plain\n" {
+		t.Errorf("expected the plain backup, but got '%s'", data)
+	}
+
+	h.Write([]byte("compressed\n"))
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The gzip stream has not been closed.
+	data, err := ioutil.ReadAll(r)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		t.Fatal(err)
+	} else if string(data) != "compressed\n" {
+		t.Errorf("expected 'compressed', but got '%s'", data)
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+const jsonArrayTailSize = 4096
+
+var (
+	jsonArrayStart = []byte("[\n")
+	jsonArraySep   = []byte(",\n")
+	jsonArrayEnd   = []byte("\n]\n")
+)
+
+// JSONArrayHandler is a rotating logging handler based on the size like
+// SizedRotatingFile, but it writes each record as an element of a JSON array,
+// so that the file and every rotated backup are a valid JSON document.
+//
+// It writes "[" when opening a new file, separates the records by commas,
+// and writes "]" when closing or rotating the file.
+//
+// The startup banner is not supported, because it makes the file invalid
+// JSON. So it's not written even if set.
+//
+// Recovery: if the process crashed, the active file misses the closing "]".
+// When reopening an existing file, the handler strips the closing "]" of
+// a cleanly closed array, or continues the unclosed array of a crashed one,
+// so the file becomes valid again after the next Close. However, a record
+// torn by the crash cannot be repaired automatically and must be removed
+// by hand.
+type JSONArrayHandler struct {
+	r     *SizedRotatingFile
+	first bool
+}
+
+// NewJSONArrayHandler returns a new JSONArrayHandler.
+//
+// The arguments are the same as NewSizedRotatingFile. If failed, it will panic.
+func NewJSONArrayHandler(filename string, size, count int) *JSONArrayHandler {
+	first, err := repairJSONArray(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	h := &JSONArrayHandler{first: first}
+	h.r = newSizedRotatingFile(filename, int64(size), count)
+	h.r.onOpen = h.onOpen
+	h.r.onClose = h.onClose
+	if err = h.r.open(); err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func (h *JSONArrayHandler) onOpen(w io.Writer, size int64) (int, error) {
+	if size > 0 {
+		return 0, nil
+	}
+	h.first = true
+	return w.Write(jsonArrayStart)
+}
+
+func (h *JSONArrayHandler) onClose(w io.Writer) (err error) {
+	_, err = w.Write(jsonArrayEnd)
+	return
+}
+
+// Write writes the data as a record of the JSON array, which may rotate
+// the file if necessary.
+//
+// The data should be a valid JSON value, and the leading and trailing spaces
+// will be removed. Notice: the data is not validated.
+func (h *JSONArrayHandler) Write(data []byte) (n int, err error) {
+	record := bytes.TrimSpace(data)
+
+	h.r.Lock()
+	defer h.r.Unlock()
+
+	// Count the closing "]" written on rollover, so the file including it
+	// doesn't exceed the max size.
+	size := len(jsonArraySep) + len(record) + len(jsonArrayEnd)
+	if err = h.r.checkRollover(size); err != nil {
+		return
+	}
+
+	if h.first {
+		h.first = false
+	} else if _, err = h.r.write(jsonArraySep); err != nil {
+		return
+	}
+
+	if _, err = h.r.write(record); err != nil {
+		return
+	}
+	return len(data), nil
+}
+
+// WriteString writes the string data as a record of the JSON array.
+func (h *JSONArrayHandler) WriteString(data string) (n int, err error) {
+	return h.Write([]byte(data))
+}
+
+// Close closes the handler, which will close the JSON array.
+func (h *JSONArrayHandler) Close() error {
+	return h.r.Close()
+}
+
+// repairJSONArray prepares the existing file to continue the JSON array,
+// and returns true if the array has no record.
+func repairJSONArray(filename string) (first bool, err error) {
+	f, err := os.OpenFile(filename, os.O_RDWR, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+
+	size := info.Size()
+	offset := size - jsonArrayTailSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	tail := make([]byte, size-offset)
+	if _, err = f.ReadAt(tail, offset); err != nil {
+		return
+	}
+
+	tail = bytes.TrimRight(tail, " \t\r\n")
+	if len(tail) > 0 && tail[len(tail)-1] == ']' {
+		tail = bytes.TrimRight(tail[:len(tail)-1], " \t\r\n")
+	}
+
+	if len(tail) == 0 && offset == 0 {
+		// The file has no content, so the new array will be started.
+		return true, f.Truncate(0)
+	}
+
+	if err = f.Truncate(offset + int64(len(tail))); err != nil {
+		return
+	}
+	return len(tail) > 0 && tail[len(tail)-1] == '[', nil
+}
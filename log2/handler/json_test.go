@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func readJSONArray(t *testing.T, filename string) (records []map[string]int) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("%s is not a valid JSON array: %s: %q", filename, err, data)
+	}
+	return
+}
+
+func TestJSONArrayHandler(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.json")
+	h := NewJSONArrayHandler(filename, 64, 100)
+	for i := 0; i < 20; i++ {
+		if _, err := h.WriteString(fmt.Sprintf(`{"id": %d}`+"\n", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Close()
+
+	var ids []int
+	files := []string{filename}
+	for i := 1; ; i++ {
+		fn := fmt.Sprintf("%s.%d", filename, i)
+		if _, err := ioutil.ReadFile(fn); err != nil {
+			break
+		}
+		files = append([]string{fn}, files...)
+	}
+	if len(files) < 2 {
+		t.Fatalf("expected the rotation, but got %d files", len(files))
+	}
+
+	for _, fn := range files {
+		if data, _ := ioutil.ReadFile(fn); len(data) > 64 {
+			t.Errorf("%s: expected the size not to exceed 64, but got %d", fn, len(data))
+		}
+		for _, record := range readJSONArray(t, fn) {
+			ids = append(ids, record["id"])
+		}
+	}
+	for i, id := range ids {
+		if i != id {
+			t.Fatalf("expected the record %d, but got %d", i, id)
+		}
+	}
+	if len(ids) != 20 {
+		t.Errorf("expected 20 records, but got %d", len(ids))
+	}
+}
+
+func TestJSONArrayHandlerRecovery(t *testing.T) {
+	dir := t.TempDir()
+	for _, content := range []string{
+		"",
+		"[\n",
+		"[\n\n]\n",
+		"[\n{\"id\": 0}",
+		"[\n{\"id\": 0}\n]\n",
+	} {
+		filename := filepath.Join(dir, "test.json")
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		h := NewJSONArrayHandler(filename, 1024, 1)
+		h.WriteString(`{"id": 1}`)
+		h.Close()
+
+		records := readJSONArray(t, filename)
+		if n := len(records); n == 0 || records[n-1]["id"] != 1 {
+			t.Errorf("%q: unexpected records %v", content, records)
+		}
+	}
+}
+
+func TestJSONArrayHandlerBanner(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.json")
+	h := NewJSONArrayHandler(filename, 1024, 1)
+	h.r.SetStartupBanner(func() []byte { return []byte("# banner\n") })
+	h.WriteString(`{"id": 1}`)
+	h.Close()
+
+	if records := readJSONArray(t, filename); len(records) != 1 || records[0]["id"] != 1 {
+		t.Errorf("unexpected the records: %v", records)
+	}
+}
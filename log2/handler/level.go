@@ -0,0 +1,25 @@
+package handler
+
+import "fmt"
+
+// Level is the level of the logging record.
+type Level int
+
+// Predefine some levels.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levels = []string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// String implements the interface fmt.Stringer.
+func (l Level) String() string {
+	if l >= LevelDebug && l <= LevelFatal {
+		return levels[l]
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
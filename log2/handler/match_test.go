@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSizedRotatingFileRotateOnMatch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 1024, 5)
+	h.SetRotateOnMatch(regexp.MustCompile(`^=== RUN `))
+
+	h.WriteString("=== RUN TestA\n")
+	h.WriteString("a line 1\n")
+	h.WriteString("a line 2\n=== RUN TestB\nb line 1\n=== RUN TestC\n")
+	h.WriteString("c line 1\n")
+	h.Close()
+
+	expected := map[string]string{
+		filename + ".2": "=== RUN TestA\na line 1\na line 2\n",
+		filename + ".1": "=== RUN TestB\nb line 1\n",
+		filename:        "=== RUN TestC\nc line 1\n",
+	}
+	for name, content := range expected {
+		if data, _ := ioutil.ReadFile(name); string(data) != content {
+			t.Errorf("%s: expected %q, but got %q", name, content, data)
+		}
+	}
+
+	// The first matching line does not leave an empty backup.
+	if backups, _ := h.Backups(); len(backups) != 2 {
+		t.Errorf("expected 2 backups, but got %v", backups)
+	}
+}
+
+func TestMatchLine(t *testing.T) {
+	re := regexp.MustCompile(`^mark`)
+	data := []byte("a\nmark 1\nb\nmark 2")
+	if i := matchLine(re, data, 0); i != 2 {
+		t.Errorf("expected %d, but got %d", 2, i)
+	}
+	if i := matchLine(re, data, 9); i != 11 {
+		t.Errorf("expected %d, but got %d", 11, i)
+	}
+	if i := matchLine(re, []byte("a\nb\n"), 0); i != -1 {
+		t.Errorf("expected %d, but got %d", -1, i)
+	}
+}
+
+func TestSizedRotatingFileRotateOnMatchPartialLine(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 1024, 5)
+	h.SetRotateOnMatch(regexp.MustCompile(`^=== RUN `))
+
+	h.WriteString("first\n")
+	h.WriteString("foo ")
+	h.WriteString("=== RUN x\n") // The continuation of "foo ".
+	h.WriteString("=== RUN y\n")
+	h.Close()
+
+	expected := map[string]string{
+		filename + ".1": "first\nfoo === RUN x\n",
+		filename:        "=== RUN y\n",
+	}
+	for name, content := range expected {
+		if data, _ := ioutil.ReadFile(name); string(data) != content {
+			t.Errorf("%s: expected %q, but got %q", name, content, data)
+		}
+	}
+}
+
+func TestSizedRotatingFileRotateOnMatchOversized(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 16, 5)
+	h.SetAtomicRecords(true)
+	h.SetRotateOnMatch(regexp.MustCompile(`^=== RUN `))
+
+	big := strings.Repeat("x", 32) + "\n"
+	h.WriteString(big) // The file exceeds the max size.
+	h.WriteString("=== RUN y\n")
+	h.Close()
+
+	// The matching line rotates the file only once, without the empty backup.
+	if data, _ := ioutil.ReadFile(filename + ".1"); string(data) != big {
+		t.Errorf("expected %q, but got %q", big, data)
+	}
+	if backups, _ := h.Backups(); len(backups) != 1 {
+		t.Errorf("expected 1 backup, but got %v", backups)
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Metrics is the snapshot of the metrics of MeteredHandler.
+type Metrics struct {
+	Bytes     uint64 // The total bytes written successfully.
+	Records   uint64 // The total records, that's, the calls of Write.
+	Errors    uint64 // The total write errors.
+	Rotations uint64 // The total rotations of the underlying handler.
+}
+
+// MeteredHandler wraps the writer and tracks the volume of the writes,
+// that's, the written bytes and records, the write errors and the rotations,
+// which are updated atomically, so Metrics is safe to be called concurrently.
+//
+// The rotations are counted only if the underlying writer supports
+// the rotate callback, such as SizedRotatingFile and TimedRotatingFile.
+type MeteredHandler struct {
+	w  io.WriteCloser
+	cb func(n int, err error)
+
+	bytes     uint64
+	records   uint64
+	errors    uint64
+	rotations uint64
+}
+
+// NewMeteredHandler returns a new MeteredHandler wrapping w.
+//
+// If w has the method SetRotateCallback(func()), it will be called to count
+// the rotations, which replaces the rotate callback set before.
+func NewMeteredHandler(w io.WriteCloser) *MeteredHandler {
+	m := &MeteredHandler{w: w}
+	if r, ok := w.(interface{ SetRotateCallback(func()) }); ok {
+		r.SetRotateCallback(func() { atomic.AddUint64(&m.rotations, 1) })
+	}
+	return m
+}
+
+// SetWriteCallback sets the callback function, which is called after each
+// write with the result of the write.
+//
+// It should be called before writing, and is not thread-safe.
+func (m *MeteredHandler) SetWriteCallback(cb func(n int, err error)) {
+	m.cb = cb
+}
+
+// Write implements the interface io.Writer.
+func (m *MeteredHandler) Write(data []byte) (n int, err error) {
+	n, err = m.w.Write(data)
+	atomic.AddUint64(&m.records, 1)
+	atomic.AddUint64(&m.bytes, uint64(n))
+	if err != nil {
+		atomic.AddUint64(&m.errors, 1)
+	}
+
+	if m.cb != nil {
+		m.cb(n, err)
+	}
+	return
+}
+
+// WriteString writes the string by Write.
+func (m *MeteredHandler) WriteString(data string) (n int, err error) {
+	return m.Write([]byte(data))
+}
+
+// Close closes the underlying writer.
+func (m *MeteredHandler) Close() error {
+	return m.w.Close()
+}
+
+// Metrics returns the snapshot of the metrics.
+func (m *MeteredHandler) Metrics() Metrics {
+	return Metrics{
+		Bytes:     atomic.LoadUint64(&m.bytes),
+		Records:   atomic.LoadUint64(&m.records),
+		Errors:    atomic.LoadUint64(&m.errors),
+		Rotations: atomic.LoadUint64(&m.rotations),
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestMeteredHandler(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	m := NewMeteredHandler(NewSizedRotatingFile(filename, 64, 2))
+
+	var calls int
+	m.SetWriteCallback(func(n int, err error) { calls++ })
+
+	for i := 0; i < 10; i++ {
+		m.WriteString("0123456789abcdef\n") // 17 bytes
+	}
+	m.Close()
+
+	if _, err := m.WriteString("after closed\n"); err != ErrFileNotOpen {
+		t.Errorf("expected ErrFileNotOpen, but got %v", err)
+	}
+
+	expected := Metrics{Bytes: 170, Records: 11, Errors: 1, Rotations: 3}
+	if metrics := m.Metrics(); metrics != expected {
+		t.Errorf("expected %+v, but got %+v", expected, metrics)
+	}
+	if calls != 11 {
+		t.Errorf("expected 11 callbacks, but got %d", calls)
+	}
+}
+
+func TestMeteredHandlerConcurrent(t *testing.T) {
+	m := NewMeteredHandler(NewAsyncHandler(new(testLockedWriter), 10))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m.WriteString("line\n")
+				m.Metrics()
+			}
+		}()
+	}
+	wg.Wait()
+	m.Close()
+
+	if metrics := m.Metrics(); metrics.Records != 400 || metrics.Bytes != 2000 {
+		t.Errorf("unexpected metrics: %+v", metrics)
+	}
+}
@@ -0,0 +1,26 @@
+package handler
+
+import "sync"
+
+// maxPooledBufferSize is the maximal capacity of the buffer put back into
+// bufferPool, so the pool doesn't hold the big buffers for the rare long lines.
+const maxPooledBufferSize = 64 * 1024
+
+var bufferPool = sync.Pool{New: func() interface{} {
+	buf := make([]byte, 0, 1024)
+	return &buf
+}}
+
+// writeString writes the string data by write with a buffer from bufferPool
+// instead of converting it to []byte, which allocates on every call.
+//
+// It's safe because write, as io.Writer, must not retain the data.
+func writeString(write func([]byte) (int, error), data string) (n int, err error) {
+	buf := bufferPool.Get().(*[]byte)
+	*buf = append((*buf)[:0], data...)
+	n, err = write(*buf)
+	if cap(*buf) <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteStringAllocs(t *testing.T) {
+	dir := t.TempDir()
+	timed := NewTimedRotatingFile(filepath.Join(dir, "timed.log"), 1)
+	defer timed.Close()
+	sized := NewSizedRotatingFile(filepath.Join(dir, "sized.log"), 1<<30, 1)
+	defer sized.Close()
+
+	const line = "a small log line to be written repeatedly\n"
+	for name, w := range map[string]interface {
+		WriteString(string) (int, error)
+	}{"timed": timed, "sized": sized} {
+		w.WriteString(line) // Warm up the buffer pool.
+		allocs := testing.AllocsPerRun(100, func() { w.WriteString(line) })
+		if allocs > 0 {
+			t.Errorf("%s: expected no allocation, but got %v", name, allocs)
+		}
+	}
+}
+
+func BenchmarkSizedRotatingFileWriteString(b *testing.B) {
+	h := NewSizedRotatingFile(filepath.Join(b.TempDir(), "test.log"), 1<<30, 1)
+	defer h.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.WriteString("a small log line to be written repeatedly\n")
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xgfone/go-tools/file"
+)
+
+const gzipSuffix = ".gz"
+
+// OpenReader returns a reader to read the full history of the log file,
+// that's, all the rotated backups from the oldest to the newest, followed by
+// the active file, as one stream.
+//
+// The backups are discovered by the filename like the rotating handlers:
+// "filename.N" of SizedRotatingFile are ordered from the biggest N, and
+// "filename.2006-01-02" of TimedRotatingFile and "filename.2006-01-02T15-04"
+// of CronRotatingFile by the time. The files compressed by gzip, such as
+// the ".gz" backups or the files written by GzipWrapper, are decompressed
+// transparently.
+//
+// The files are opened lazily one by one while reading, and the file removed
+// by the rotation in the meantime is skipped.
+func OpenReader(filename string) (io.ReadCloser, error) {
+	filename = absFilename(filename)
+	backups, err := listAllBackups(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &segmentReader{files: append(backups, filename)}, nil
+}
+
+// listAllBackups returns all the backups of the log file in the chronological
+// order, which contains the compressed ones.
+func listAllBackups(filename string) ([]string, error) {
+	dirName, baseName := filepath.Split(filename)
+	fileNames, err := file.ListDir2(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The dated file, which the symbolic link "filename" points to,
+	// is the active file instead of a backup.
+	var active string
+	if isSymlink(filename) {
+		if active, err = filepath.EvalSymlinks(filename); err != nil {
+			return nil, err
+		}
+		active = filepath.Base(active)
+	}
+
+	type sizedBackup struct {
+		name  string
+		index int
+	}
+
+	var dated []string
+	var sized []sizedBackup
+	prefix := baseName + "."
+	for _, fileName := range fileNames {
+		if !strings.HasPrefix(fileName, prefix) || fileName == active {
+			continue
+		}
+
+		suffix := strings.TrimSuffix(fileName[len(prefix):], gzipSuffix)
+		if i, err := strconv.Atoi(suffix); err == nil && i > 0 {
+			sized = append(sized, sizedBackup{name: fileName, index: i})
+		} else if (dayRE.MatchString(suffix) || cronRE.MatchString(suffix)) &&
+			!strings.HasSuffix(suffix, indexSuffix) {
+			dated = append(dated, fileName)
+		}
+	}
+
+	sort.Strings(dated)
+	sort.Slice(sized, func(i, j int) bool { return sized[i].index > sized[j].index })
+
+	backups := make([]string, 0, len(dated)+len(sized))
+	for _, fileName := range dated {
+		backups = append(backups, filepath.Join(dirName, fileName))
+	}
+	for _, backup := range sized {
+		backups = append(backups, filepath.Join(dirName, backup.name))
+	}
+	return backups, nil
+}
+
+// segmentReader reads the files one by one as one stream.
+type segmentReader struct {
+	files []string
+	cur   io.Reader
+	close func() error
+}
+
+func (r *segmentReader) Read(p []byte) (n int, err error) {
+	for {
+		if r.cur == nil {
+			if len(r.files) == 0 {
+				return 0, io.EOF
+			}
+
+			filename := r.files[0]
+			r.files = r.files[1:]
+			if err = r.open(filename); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return
+			}
+		}
+
+		n, err = r.cur.Read(p)
+		if err == io.EOF {
+			if err = r.closeCurrent(); err != nil || n > 0 {
+				return
+			}
+			continue
+		}
+		return
+	}
+}
+
+func (r *segmentReader) open(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(f)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return err
+		}
+
+		r.cur = gz
+		r.close = func() error { gz.Close(); return f.Close() }
+		return nil
+	}
+
+	r.cur = br
+	r.close = f.Close
+	return nil
+}
+
+func (r *segmentReader) closeCurrent() (err error) {
+	if r.cur != nil {
+		err = r.close()
+		r.cur = nil
+		r.close = nil
+	}
+	return
+}
+
+// Close closes the file being read, and the rest are not read any more.
+func (r *segmentReader) Close() error {
+	r.files = nil
+	return r.closeCurrent()
+}
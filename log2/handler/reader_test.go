@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func gzipFile(t *testing.T, filename string) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(filename + gzipSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write(data)
+	gz.Close()
+	f.Close()
+	os.Remove(filename)
+}
+
+func TestOpenReader(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 64, 100)
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(h, "line %02d\n", i)
+	}
+	h.Close()
+
+	backups, err := h.Backups()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(backups) < 3 {
+		t.Fatalf("expected 3 backups at least, but got %d", len(backups))
+	}
+	gzipFile(t, backups[1])
+	ioutil.WriteFile(filename+".1"+indexSuffix, []byte("ignored"), 0644)
+
+	r, err := OpenReader(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var i int
+	scanner := bufio.NewScanner(r)
+	for ; scanner.Scan(); i++ {
+		if line := fmt.Sprintf("line %02d", i); scanner.Text() != line {
+			t.Fatalf("expected '%s', but got '%s'", line, scanner.Text())
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	} else if i != 40 {
+		t.Errorf("expected 40 lines, but got %d", i)
+	}
+}
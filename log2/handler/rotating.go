@@ -2,6 +2,7 @@
 package handler
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,7 +20,8 @@ import (
 )
 
 const (
-	// FileMode is the mode to open the log file.
+	// FileMode is the mode to open the log file, which is the same as
+	// file.OpenAppend.
 	FileMode = os.O_APPEND | os.O_CREATE | os.O_WRONLY
 
 	// FilePerm is the default permission to open the log file.
@@ -33,11 +37,22 @@ var (
 	}
 
 	filePerm = FilePerm
+
+	// timeNow returns the current time, which may be replaced in the tests.
+	timeNow = time.Now
+
+	// filepathAbs is used to get the absolute path of the log file,
+	// which may be replaced in the tests.
+	filepathAbs = filepath.Abs
 )
 
 var (
 	// ErrFileNotOpen is the error to open the log file.
 	ErrFileNotOpen = errors.New("The file is not opened")
+
+	// ErrDiskFull is returned when the free space of the disk is below
+	// the threshold.
+	ErrDiskFull = errors.New("The free space of the disk is below the threshold")
 )
 
 // ResetDefaultFilePerm resets the default permission to open the log file.
@@ -57,20 +72,59 @@ type TimedRotatingFile struct {
 	interval    int64
 	when        int64
 	rotatorAt   int64
+	periodAt    time.Time
 	extRE       *regexp.Regexp
+	layout      string
+	banner      func() []byte
+	dated       bool
+	onRotate    func()
+	onError     func(error)
+	seq         *lineSequencer
+	durable     bool
+	syncDir     func(dir string) error
+	fs          FileSystem
+
+	// The directories to archive the new backups and the evicted ones,
+	// see SetArchiveDir and SetColdArchiveDir.
+	archiveDir     string
+	coldArchiveDir string
+
+	// The evicted backups being compressed into the cold archive directory.
+	evicting map[string]struct{}
+	evictWG  sync.WaitGroup
+
+	// next returns the next rollover time after the given time, which
+	// replaces the rollover by the interval if set, such as CronRotatingFile.
+	next func(time.Time) time.Time
+
+	// The sidecar time index of the file being written, see SetTimeIndex.
+	index         File
+	indexInterval time.Duration
+	indexedAt     time.Time
+	offset        int64
 }
 
 // NewTimedRotatingFile creates a new TimedRotatingFile.
 //
 // If failed, it will panic.
 func NewTimedRotatingFile(filename string, count int) *TimedRotatingFile {
-	filename = file.Abs(filename)
+	return NewTimedRotatingFileFS(OSFS, filename, count)
+}
+
+// NewTimedRotatingFileFS is the same as NewTimedRotatingFile, but opens,
+// rotates, archives and lists the log files on the filesystem fs, such as
+// MemFS in the tests.
+func NewTimedRotatingFileFS(fs FileSystem, filename string, count int) *TimedRotatingFile {
+	filename = absFilename(filename)
 	t := TimedRotatingFile{
 		filename:    filename,
 		when:        day,
 		extRE:       dayRE,
+		layout:      time2fmt[day],
 		backupCount: count,
 		interval:    day,
+		syncDir:     fsyncDir,
+		fs:          fs,
 	}
 	t.reComputeRollover()
 	if err := t.open(); err != nil {
@@ -79,9 +133,22 @@ func NewTimedRotatingFile(filename string, count int) *TimedRotatingFile {
 	return &t
 }
 
+// absFilename returns the absolute path of the log file, so that the file
+// is not affected by changing the current working directory later.
+//
+// If failing to get the absolute path, return the filename itself, whose
+// leading "~" has been expanded.
+func absFilename(filename string) string {
+	filename = file.ExpandHome(filename)
+	if abs, err := filepathAbs(filename); err == nil && abs != "" {
+		return abs
+	}
+	return filename
+}
+
 // WriteString writes the string data into the file, which may rotate the file if necessary.
 func (t *TimedRotatingFile) WriteString(data string) (n int, err error) {
-	return t.Write([]byte(data))
+	return writeString(t.Write, data)
 }
 
 // Write writes the byte slice data into the file, which may rotate the file if necessary.
@@ -100,16 +167,47 @@ func (t *TimedRotatingFile) Write(data []byte) (n int, err error) {
 		}
 	}
 
+	if t.seq != nil {
+		if _, err = t.write(t.seq.prefix(data)); err != nil {
+			return
+		}
+		return len(data), nil
+	}
+	return t.write(data)
+}
+
+func (t *TimedRotatingFile) write(data []byte) (n int, err error) {
+	if t.index != nil {
+		if err = t.writeIndex(); err != nil {
+			return
+		}
+		n, err = t.w.Write(data)
+		t.offset += int64(n)
+		return
+	}
+
 	return t.w.Write(data)
 }
 
 func (t *TimedRotatingFile) shouldRollover() bool {
-	return time.Now().Unix() >= t.rotatorAt
+	return timeNow().Unix() >= t.rotatorAt
 }
 
 // Close closes the handler.
 // Return ErrFileNotOpen when to write the data to the handler after closed.
+//
+// If the cold archive directory is set, it blocks until the evicted backups
+// being compressed in the background have been compressed.
 func (t *TimedRotatingFile) Close() (err error) {
+	err = t.close()
+	t.evictWG.Wait()
+	return
+}
+
+func (t *TimedRotatingFile) close() (err error) {
+	if err = t.closeIndex(); err != nil {
+		return
+	}
 	if err = t.w.Close(); err != nil {
 		return
 	}
@@ -117,48 +215,269 @@ func (t *TimedRotatingFile) Close() (err error) {
 	return
 }
 
+// Sync commits the current file to the stable storage, so it implements
+// the interface Syncer for GroupCommitHandler.
+func (t *TimedRotatingFile) Sync() (err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.w == nil {
+		return ErrFileNotOpen
+	}
+	if s, ok := t.w.(interface{ Sync() error }); ok {
+		err = s.Sync()
+	}
+	return
+}
+
+// SetActiveDated controls whether the file being written carries the date
+// suffix, such as "app.log.2006-01-02", which is false by default.
+//
+// If true, the file is born dated, and "filename" is a symbolic link
+// pointing to it, so it needs not to be renamed on rollover. Or, the file
+// being written is always "filename", which is renamed with the date suffix
+// on rollover.
+//
+// When switching, the current file is renamed between "filename" and the dated
+// one, so the data written in the current period stays in the same file.
+func (t *TimedRotatingFile) SetActiveDated(dated bool) (err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if dated == t.dated {
+		return
+	}
+
+	opened := t.w != nil
+	if opened {
+		if err = t.close(); err != nil {
+			return
+		}
+	}
+
+	datedPath := t.datedFilename()
+	if dated {
+		if !t.isSymlink(t.filename) && t.isFile(t.filename) {
+			err = t.renameWithIndex(t.filename, datedPath)
+		}
+	} else if t.isSymlink(t.filename) {
+		if err = t.fs.Remove(t.filename); err == nil && t.isFile(datedPath) {
+			err = t.renameWithIndex(datedPath, t.filename)
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	t.dated = dated
+	if opened {
+		err = t.open()
+	}
+	return
+}
+
+func isSymlink(filename string) bool {
+	fi, err := os.Lstat(filename)
+	return err == nil && fi.Mode()&os.ModeSymlink != 0
+}
+
+func (t *TimedRotatingFile) isSymlink(filename string) bool {
+	fi, err := t.fs.Lstat(filename)
+	return err == nil && fi.Mode()&os.ModeSymlink != 0
+}
+
+func (t *TimedRotatingFile) isFile(filename string) bool {
+	fi, err := t.fs.Stat(filename)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+func (t *TimedRotatingFile) exist(filename string) bool {
+	_, err := t.fs.Stat(filename)
+	return err == nil
+}
+
+// datedFilename returns the filename with the date suffix of the current period.
+func (t *TimedRotatingFile) datedFilename() string {
+	return t.filename + "." + t.periodAt.Format(t.layout)
+}
+
+// activeFilename returns the filename of the file being written.
+func (t *TimedRotatingFile) activeFilename() string {
+	if t.dated {
+		return t.datedFilename()
+	}
+	return t.filename
+}
+
+// linkActiveFile makes "filename" a symbolic link to the dated file being
+// written. But it's not done if a regular file exists with "filename".
+func (t *TimedRotatingFile) linkActiveFile(active string) error {
+	if t.isSymlink(t.filename) {
+		if err := t.fs.Remove(t.filename); err != nil {
+			return err
+		}
+	} else if t.exist(t.filename) {
+		return nil
+	}
+	return t.fs.Symlink(filepath.Base(active), t.filename)
+}
+
 func (t *TimedRotatingFile) open() error {
-	file, err := os.OpenFile(t.filename, FileMode, FilePerm)
+	if err := t.fs.MkdirAll(filepath.Dir(t.filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	active := t.activeFilename()
+	f, err := t.fs.OpenAppend(active, filePerm)
 	if err != nil {
 		return err
 	}
-	t.w = file
-	return nil
+
+	if t.dated {
+		if err = t.linkActiveFile(active); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	t.w = f
+
+	if err = t.writeBanner(); err == nil {
+		err = t.openIndex()
+	}
+	if err != nil {
+		t.w = nil
+		f.Close()
+	}
+	return err
 }
 
-func (t *TimedRotatingFile) doRollover() (err error) {
-	if err = t.Close(); err != nil {
+// SetStartupBanner sets the function to return the banner, such as the pid
+// and the hostname, which is written at the top of every new empty file.
+//
+// If the current file is empty, the banner is written into it at once.
+func (t *TimedRotatingFile) SetStartupBanner(banner func() []byte) error {
+	t.Lock()
+	defer t.Unlock()
+
+	t.banner = banner
+	if t.w == nil {
+		return nil
+	}
+	return t.writeBanner()
+}
+
+// SetRotateCallback sets the callback function, which is called after
+// rotating the file successfully, such as counting the rotations.
+//
+// The callback is called with the lock of the handler held, so it must not
+// call the methods of the handler.
+func (t *TimedRotatingFile) SetRotateCallback(cb func()) {
+	t.Lock()
+	t.onRotate = cb
+	t.Unlock()
+}
+
+// SetErrorCallback sets the callback function, which is called with
+// the error of the add-on on rollover, such as archiving the backup,
+// which doesn't fail the write triggering the rollover. If not set,
+// the error is ignored.
+//
+// The callback is called with the lock of the handler held, so it must not
+// call the methods of the handler.
+func (t *TimedRotatingFile) SetErrorCallback(cb func(err error)) {
+	t.Lock()
+	t.onError = cb
+	t.Unlock()
+}
+
+func (t *TimedRotatingFile) reportError(err error) {
+	if err != nil && t.onError != nil {
+		t.onError(err)
+	}
+}
+
+func (t *TimedRotatingFile) writeBanner() (err error) {
+	if t.banner == nil {
 		return
 	}
 
-	dstTime := t.rotatorAt - t.interval
-	dstPath := t.filename + "." + time.Unix(dstTime, 0).Format(time2fmt[t.when])
-	if file.IsExist(dstPath) {
-		os.Remove(dstPath)
+	if fi, err := t.fs.Stat(t.activeFilename()); err != nil || fi.Size() > 0 {
+		return err
 	}
+	_, err = t.w.Write(t.banner())
+	return
+}
 
-	if file.IsFile(t.filename) {
-		if err = os.Rename(t.filename, dstPath); err != nil {
-			return err
+func (t *TimedRotatingFile) doRollover() (err error) {
+	if err = t.close(); err != nil {
+		return
+	}
+
+	var syncErr error
+	backup := t.datedFilename()
+	if !t.dated {
+		if t.exist(backup) {
+			t.fs.Remove(backup)
+		}
+
+		if t.isFile(t.filename) {
+			if err = t.renameWithIndex(t.filename, backup); err != nil {
+				return err
+			}
+			if t.durable && t.fs == OSFS {
+				syncErr = t.syncDir(filepath.Dir(t.filename))
+			}
 		}
 	}
 
 	if t.backupCount > 0 {
 		for _, file := range t.getFilesToDelete() {
-			os.Remove(file)
+			t.evict(file)
 		}
 	}
 
 	t.reComputeRollover()
-	return t.open()
+	if err = t.open(); err != nil {
+		return
+	}
+
+	// Sync the directory and archive the backup before, and report
+	// the failure by the error callback after, reopening the file,
+	// so the write triggering the rollover is not dropped because of it.
+	t.reportError(syncErr)
+	t.reportError(t.archive(backup))
+	if t.onRotate != nil {
+		t.onRotate()
+	}
+	return
+}
+
+// Backups returns the paths of the backup files in the chronological order.
+func (t *TimedRotatingFile) Backups() ([]string, error) {
+	t.Lock()
+	defer t.Unlock()
+	return t.listBackups()
 }
 
 func (t *TimedRotatingFile) getFilesToDelete() []string {
+	result, err := t.listBackups()
+	if err != nil || len(result) <= t.backupCount {
+		return []string{}
+	}
+	return result[:len(result)-t.backupCount]
+}
+
+func (t *TimedRotatingFile) listBackups() ([]string, error) {
 	result := make([]string, 0, 30)
 	dirName, baseName := filepath.Split(t.filename)
-	fileNames, err := file.ListDir2(dirName)
+	fileNames, err := t.fs.ReadDir(dirName)
 	if err != nil {
-		return result
+		return nil, err
+	}
+
+	var active string
+	if t.dated {
+		active = t.datedFilename()
 	}
 
 	var suffix, prefix string
@@ -171,82 +490,449 @@ func (t *TimedRotatingFile) getFilesToDelete() []string {
 		prefix = string(fileName[:plen])
 		if _prefix == prefix {
 			suffix = string(fileName[plen:])
-			if t.extRE.MatchString(suffix) {
-				result = append(result, filepath.Join(dirName, fileName))
+			if t.extRE.MatchString(suffix) && !strings.HasSuffix(suffix, indexSuffix) {
+				if fp := filepath.Join(dirName, fileName); fp != active {
+					result = append(result, fp)
+				}
 			}
 		}
 	}
 
-	if len(result) <= t.backupCount {
-		return []string{}
-	}
 	sort.Strings(result)
-	return result[:len(result)-t.backupCount]
+	return result, nil
 }
 
+// reComputeRollover computes the start of the current period, that's,
+// the local midnight of today, and the next rollover time by time.Date,
+// so the rollover lands at the local midnight even across the DST
+// transitions, when the day is not 24 hours.
+//
+// If next is set, the current period starts from the current minute instead,
+// and the next rollover time is computed by next.
 func (t *TimedRotatingFile) reComputeRollover() {
-	currentTime := time.Now().Unix()
-
-	_time := time.Unix(currentTime, 0)
-	currentHour := _time.Hour()
-	currentMinute := _time.Minute()
-	currentSecond := _time.Second()
+	now := timeNow()
+	if t.next != nil {
+		t.periodAt = now.Truncate(time.Minute)
+		t.rotatorAt = t.next(now).Unix()
+		return
+	}
 
-	r := t.interval - int64((currentHour*60+currentMinute)*60+currentSecond)
-	t.rotatorAt = currentTime + r
+	y, m, d := now.Date()
+	t.periodAt = time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	t.rotatorAt = t.periodAt.AddDate(0, 0, int(t.interval/t.when)).Unix()
 }
 
 // SizedRotatingFile is a rotating logging handler based on the size.
 type SizedRotatingFile struct {
 	sync.Mutex
 	w *WriteCloser
+	f File // the underlying file of w
 
 	filename    string
-	maxSize     int
+	maxSize     int64
 	backupCount int
-	nbytes      int
+	nbytes      int64
+
+	// onOpen is called after opening the file, whose size is given,
+	// and onClose is called before closing the file. They are used
+	// by the handlers wrapping SizedRotatingFile, such as JSONArrayHandler,
+	// which owns the format of the file, so the banner is not written.
+	onOpen  func(w io.Writer, size int64) (n int, err error)
+	onClose func(w io.Writer) (err error)
+
+	// wrap wraps the opened file, such as GzipWrapper.
+	wrap func(io.WriteCloser) io.WriteCloser
+
+	marker   []byte
+	banner   func() []byte
+	onRotate func()
+	onError  func(error)
+	seq      *lineSequencer
+	atomic   bool
+	match    *regexp.Regexp
+	matchMid bool // Whether the last write ends in the middle of a line.
+
+	// empty reports whether no record has been written into the file,
+	// which is empty when opened, except the banner and the header written
+	// by onOpen.
+	empty bool
+
+	minFree   int64
+	freeBytes func(dir string) (int64, error)
+
+	durable bool
+	syncDir func(dir string) error
+
+	fs FileSystem
 }
 
 // NewSizedRotatingFile returns a new RotatingFile.
+//
+// The max size is int for the compatibility, which is limited to 2GB on
+// the 32-bit platforms. Use NewSizedRotatingFile64 for the bigger size.
 func NewSizedRotatingFile(filename string, size, count int) *SizedRotatingFile {
-	r := &SizedRotatingFile{
-		filename:    filename,
-		maxSize:     size,
-		backupCount: count,
+	return NewSizedRotatingFile64(filename, int64(size), count)
+}
+
+// NewSizedRotatingFile64 is the same as NewSizedRotatingFile, but the max size
+// is int64, which is not limited by the size of int on the 32-bit platforms.
+func NewSizedRotatingFile64(filename string, size int64, count int) *SizedRotatingFile {
+	r := newSizedRotatingFile(filename, size, count)
+	if err := r.open(); err != nil {
+		panic(err)
 	}
+	return r
+}
 
+// NewSizedRotatingFileFS is the same as NewSizedRotatingFile, but opens,
+// rotates and lists the log files on the filesystem fs, such as MemFS
+// in the tests.
+func NewSizedRotatingFileFS(fs FileSystem, filename string, size, count int) *SizedRotatingFile {
+	r := newSizedRotatingFile(filename, int64(size), count)
+	r.fs = fs
 	if err := r.open(); err != nil {
 		panic(err)
 	}
 	return r
 }
 
+func newSizedRotatingFile(filename string, size int64, count int) *SizedRotatingFile {
+	return &SizedRotatingFile{
+		filename:    filename,
+		maxSize:     size,
+		backupCount: count,
+		freeBytes:   diskFree,
+		syncDir:     fsyncDir,
+		fs:          OSFS,
+	}
+}
+
+// SetAtomicRecords controls whether each Write is guaranteed to land in one
+// file as a whole record, such as a multi-line JSON record, which is false
+// by default.
+//
+// The rollover is always checked before writing the data, so the record
+// exceeding the rest of the budget is written into the fresh file. But if
+// atomic is false, the record exceeding the max size itself rotates the file
+// even if the file is empty, which leaves an empty backup. If true, such
+// a record is written into the empty file as a whole, and the file is rotated
+// before the next Write.
+//
+// Notice: the data of a Write containing the rotate marker is split into
+// the records by the marker, see SetRotateMarker.
+func (r *SizedRotatingFile) SetAtomicRecords(atomic bool) {
+	r.Lock()
+	r.atomic = atomic
+	r.Unlock()
+}
+
+// SetRotateOnMatch sets the regexp, and the file is rolled over before
+// writing the line matching it, so the matching line starts a fresh file,
+// such as a new file for each "=== RUN" line of the tests.
+//
+// The file is not rolled over if no line has been written into it, except
+// the startup banner, so the first matching line doesn't leave an empty backup.
+//
+// If re is nil, cancel it.
+func (r *SizedRotatingFile) SetRotateOnMatch(re *regexp.Regexp) {
+	r.Lock()
+	r.match = re
+	r.Unlock()
+}
+
+// SetRotateMarker sets the marker line, which triggers a rollover when
+// writing it and is not written itself.
+//
+// When the data of a Write is equal to the marker or contains it as a full
+// line, the lines before the marker are written into the current file,
+// then the file is rotated, and the lines after the marker are written into
+// the new file. The trailing newline of the marker is ignored.
+//
+// If marker is empty, cancel it.
+func (r *SizedRotatingFile) SetRotateMarker(marker []byte) {
+	marker = bytes.TrimSuffix(marker, []byte("\n"))
+	r.Lock()
+	if len(marker) == 0 {
+		r.marker = nil
+	} else {
+		r.marker = append([]byte(nil), marker...)
+	}
+	r.Unlock()
+}
+
 // Write implements the interface io.Writer.
 func (r *SizedRotatingFile) Write(data []byte) (n int, err error) {
 	r.Lock()
 	defer r.Unlock()
 
-	if r.w == nil || r.w.Closed() {
-		err = ErrFileNotOpen
+	if err = r.checkDiskFree(); err != nil {
 		return
 	}
 
-	if r.nbytes+len(data) > r.maxSize {
-		if err = r.doRollover(); err != nil {
+	if r.match != nil {
+		return r.writeWithMatch(data)
+	}
+	return r.writeRecords(data)
+}
+
+// writeRecords writes the records, which are split by the marker if set.
+func (r *SizedRotatingFile) writeRecords(data []byte) (n int, err error) {
+	if r.marker != nil {
+		return r.writeWithMarker(data)
+	}
+	return r.writeLines(data)
+}
+
+// writeWithMatch writes the data by writeRecords, which rolls the file over
+// before each line matching the regexp set by SetRotateOnMatch.
+func (r *SizedRotatingFile) writeWithMatch(data []byte) (n int, err error) {
+	// The continuation of the line partially written by the last write
+	// is not the start of a line.
+	var offset int
+	if r.matchMid {
+		if offset = bytes.IndexByte(data, '\n') + 1; offset == 0 {
+			offset = len(data)
+		}
+	}
+
+	for len(data) > 0 {
+		start := matchLine(r.match, data, offset)
+		if start < 0 {
+			m, err := r.writeMatched(data)
+			return n + m, err
+		}
+
+		if start > 0 {
+			m, err := r.writeMatched(data[:start])
+			if n += m; err != nil {
+				return n, err
+			}
+			data = data[start:]
+		}
+
+		if !r.empty {
+			if err = r.checkOpened(); err != nil {
+				return
+			} else if err = r.doRollover(); err != nil {
+				return
+			}
+		}
+
+		// Write the matching line and the lines following it until
+		// the next matching line.
+		end := len(data)
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			if next := matchLine(r.match, data, i+1); next > 0 {
+				end = next
+			}
+		}
+
+		m, err := r.writeMatched(data[:end])
+		if n += m; err != nil {
+			return n, err
+		}
+		data, offset = data[end:], 0
+	}
+	return
+}
+
+// writeMatched writes the data by writeRecords, and records whether it ends
+// in the middle of a line.
+func (r *SizedRotatingFile) writeMatched(data []byte) (n int, err error) {
+	if n, err = r.writeRecords(data); n > 0 {
+		r.matchMid = data[n-1] != '\n'
+	}
+	return
+}
+
+// matchLine returns the start index of the first line in data from offset,
+// which matches re, or -1 if not present. offset must be the start of a line.
+func matchLine(re *regexp.Regexp, data []byte, offset int) int {
+	for offset < len(data) {
+		end := len(data)
+		if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+			end = offset + i
+		}
+
+		if re.Match(data[offset:end]) {
+			return offset
+		}
+		offset = end + 1
+	}
+	return -1
+}
+
+// writeLines writes the lines by writeData, which are prefixed by
+// the sequence numbers if enabled.
+func (r *SizedRotatingFile) writeLines(data []byte) (n int, err error) {
+	if r.seq != nil {
+		if _, err = r.writeData(r.seq.prefix(data)); err != nil {
 			return
 		}
+		return len(data), nil
 	}
+	return r.writeData(data)
+}
 
+func (r *SizedRotatingFile) writeData(data []byte) (n int, err error) {
+	if err = r.checkRollover(len(data)); err != nil {
+		return
+	}
+	if n, err = r.write(data); n > 0 {
+		r.empty = false
+	}
+	return
+}
+
+func (r *SizedRotatingFile) writeWithMarker(data []byte) (n int, err error) {
+	for len(data) > 0 {
+		start := indexLine(data, r.marker)
+		if start < 0 {
+			m, err := r.writeLines(data)
+			return n + m, err
+		}
+
+		if start > 0 {
+			m, err := r.writeLines(data[:start])
+			if n += m; err != nil {
+				return n, err
+			}
+		}
+
+		end := start + len(r.marker)
+		if end < len(data) {
+			end++ // Skip the newline of the marker.
+		}
+
+		if err = r.checkOpened(); err != nil {
+			return
+		} else if err = r.doRollover(); err != nil {
+			return
+		}
+
+		n += end - start
+		data = data[end:]
+	}
+	return
+}
+
+// indexLine returns the index of the first line in data equal to line,
+// or -1 if not present.
+func indexLine(data, line []byte) int {
+	for offset := 0; offset < len(data); {
+		i := bytes.Index(data[offset:], line)
+		if i < 0 {
+			return -1
+		}
+
+		start, end := offset+i, offset+i+len(line)
+		if (start == 0 || data[start-1] == '\n') && (end == len(data) || data[end] == '\n') {
+			return start
+		}
+		offset = start + 1
+	}
+	return -1
+}
+
+// checkRollover rolls the file over if writing n bytes exceeds the max size.
+//
+// But the empty file is not rolled over for the atomic records, because the
+// record exceeding the max size itself is written into it as a whole.
+func (r *SizedRotatingFile) checkRollover(n int) (err error) {
+	if err = r.checkOpened(); err != nil {
+		return
+	}
+
+	if r.nbytes+int64(n) > r.maxSize && (!r.atomic || r.nbytes > 0) {
+		err = r.doRollover()
+	}
+	return
+}
+
+// checkOpened returns ErrFileNotOpen if the file is not opened.
+func (r *SizedRotatingFile) checkOpened() error {
+	if r.w == nil || r.w.Closed() {
+		return ErrFileNotOpen
+	}
+	return nil
+}
+
+func (r *SizedRotatingFile) write(data []byte) (n int, err error) {
 	if n, err = r.w.Write(data); err != nil {
 		return
 	}
-	r.nbytes += n
+	r.addBytes(n)
+	return
+}
+
+// addBytes counts the written bytes, which are counted by the file
+// underlying the wrapper instead if the wrapper is set.
+func (r *SizedRotatingFile) addBytes(n int) {
+	if r.wrap == nil {
+		r.nbytes += int64(n)
+	}
+}
+
+// SetWrapper sets the wrapper of the file, such as GzipWrapper, which wraps
+// every file opened by the handler. So the wrapper is closed when rotating
+// or closing the file. It reopens the current file to take effect at once.
+//
+// If the current file is not empty, it's rotated before being wrapped,
+// so the data written by the wrapper, such as the gzip stream, is not
+// appended to the data written without it. But it's not rotated if
+// the backup count is 0.
+//
+// Notice: the size for the rotation measures the bytes written into the file
+// by the wrapper, such as the compressed bytes.
+func (r *SizedRotatingFile) SetWrapper(wrap func(io.WriteCloser) io.WriteCloser) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err = r.close(); err != nil {
+		return
+	}
+	r.wrap = wrap
+	if wrap != nil && r.backupCount > 0 && r.nbytes > 0 {
+		return r.doRollover()
+	}
+	return r.open()
+}
+
+// Flush flushes the buffered data into the file, and flushes the wrapper
+// if it has the method Flush() error, such as GzipHandler, so the file
+// written by GzipWrapper is a valid gzip file up to the written data.
+func (r *SizedRotatingFile) Flush() (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err = r.checkOpened(); err == nil {
+		err = r.w.Flush()
+	}
+	return
+}
+
+// Sync flushes the buffered data like Flush, then commits the file
+// to the stable storage if it supports, such as the file on OSFS.
+// So it implements the interface Syncer for GroupCommitHandler.
+func (r *SizedRotatingFile) Sync() (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err = r.checkOpened(); err != nil {
+		return
+	} else if err = r.w.Flush(); err != nil {
+		return
+	}
+
+	if s, ok := r.f.(interface{ Sync() error }); ok {
+		err = s.Sync()
+	}
 	return
 }
 
 // WriteString writes the string.
 func (r *SizedRotatingFile) WriteString(data string) (n int, err error) {
-	return r.Write([]byte(data))
+	return writeString(r.Write, data)
 }
 
 // Close implements the interface io.Closer.
@@ -259,12 +945,120 @@ func (r *SizedRotatingFile) Close() (err error) {
 
 func (r *SizedRotatingFile) close() (err error) {
 	if r.w != nil {
-		err = r.w.Close()
+		if r.onClose != nil {
+			err = r.onClose(r.w)
+		}
+		if _err := r.w.Close(); err == nil {
+			err = _err
+		}
 		r.w = nil
+		r.f = nil
+	}
+	return
+}
+
+// SetFilename changes the log file to filename at runtime, such as when
+// reloading the configuration, which flushes and closes the current file,
+// then opens the new one, creating its directory if not exist.
+//
+// The writes are not lost, because they are blocked during switching.
+// If failing to open the new file, it reopens the old one and returns
+// the error.
+func (r *SizedRotatingFile) SetFilename(filename string) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err = r.close(); err != nil {
+		return
+	}
+
+	oldname := r.filename
+	r.filename = filename
+	if err = r.open(); err != nil {
+		r.filename = oldname
+		r.open()
+	}
+	return
+}
+
+// SetStartupBanner sets the function to return the banner, such as the pid
+// and the hostname, which is written at the top of every new empty file
+// and counted in the size of the file.
+//
+// If the current file is empty, the banner is written into it at once.
+func (r *SizedRotatingFile) SetStartupBanner(banner func() []byte) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.banner = banner
+	if r.w == nil {
+		return nil
+	}
+	return r.writeBanner()
+}
+
+// SetRotateCallback is the same as TimedRotatingFile.SetRotateCallback.
+func (r *SizedRotatingFile) SetRotateCallback(cb func()) {
+	r.Lock()
+	r.onRotate = cb
+	r.Unlock()
+}
+
+// SetErrorCallback is the same as TimedRotatingFile.SetErrorCallback,
+// which is called with the error to sync the directory on rollover.
+func (r *SizedRotatingFile) SetErrorCallback(cb func(err error)) {
+	r.Lock()
+	r.onError = cb
+	r.Unlock()
+}
+
+func (r *SizedRotatingFile) reportError(err error) {
+	if err != nil && r.onError != nil {
+		r.onError(err)
+	}
+}
+
+func (r *SizedRotatingFile) writeBanner() (err error) {
+	if r.banner != nil && r.onOpen == nil && r.nbytes == 0 {
+		_, err = r.write(r.banner())
 	}
 	return
 }
 
+// Backups returns the paths of the backup files in the numeric order,
+// that's, from the newest "filename.1" to the oldest.
+func (r *SizedRotatingFile) Backups() ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+	return r.listBackups()
+}
+
+func (r *SizedRotatingFile) listBackups() ([]string, error) {
+	dirName, baseName := filepath.Split(r.filename)
+	fileNames, err := r.fs.ReadDir(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := baseName + "."
+	indexes := make([]int, 0, r.backupCount)
+	for _, fileName := range fileNames {
+		if !strings.HasPrefix(fileName, prefix) {
+			continue
+		}
+		if i, err := strconv.Atoi(fileName[len(prefix):]); err == nil && i > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+
+	sort.Ints(indexes)
+	backups := make([]string, len(indexes))
+	for i, index := range indexes {
+		backups[i] = fmt.Sprintf("%s.%d", r.filename, index)
+	}
+	return backups, nil
+}
+
 func (r *SizedRotatingFile) doRollover() (err error) {
 	if r.backupCount > 0 {
 		if err = r.close(); err != nil {
@@ -273,41 +1067,88 @@ func (r *SizedRotatingFile) doRollover() (err error) {
 		for _, i := range function.Range(r.backupCount-1, 0, -1) {
 			sfn := fmt.Sprintf("%s.%d", r.filename, i)
 			dfn := fmt.Sprintf("%s.%d", r.filename, i+1)
-			if file.IsExist(sfn) {
-				if file.IsExist(dfn) {
-					os.Remove(dfn)
+			if r.exist(sfn) {
+				if r.exist(dfn) {
+					r.fs.Remove(dfn)
 				}
-				if err = os.Rename(sfn, dfn); err != nil {
+				if err = r.fs.Rename(sfn, dfn); err != nil {
 					return
 				}
 			}
 		}
 		dfn := r.filename + ".1"
-		if file.IsExist(dfn) {
-			if err = os.Remove(dfn); err != nil {
+		if r.exist(dfn) {
+			if err = r.fs.Remove(dfn); err != nil {
 				return
 			}
 		}
-		if file.IsExist(r.filename) {
-			if err = os.Rename(r.filename, dfn); err != nil {
+		if r.exist(r.filename) {
+			if err = r.fs.Rename(r.filename, dfn); err != nil {
 				return
 			}
 		}
-		err = r.open()
+		var syncErr error
+		if r.durable && r.fs == OSFS {
+			syncErr = r.syncDir(filepath.Dir(r.filename))
+		}
+		if err = r.open(); err != nil {
+			return
+		}
+
+		// Report the failure to sync the directory after reopening the file,
+		// so the write triggering the rollover is not dropped because of it.
+		r.reportError(syncErr)
+		if r.onRotate != nil {
+			r.onRotate()
+		}
 	}
 	return
 }
 
+func (r *SizedRotatingFile) exist(name string) bool {
+	_, err := r.fs.Stat(name)
+	return err == nil
+}
+
 func (r *SizedRotatingFile) open() (err error) {
-	file, err := os.OpenFile(r.filename, FileMode, FilePerm)
+	if err = r.fs.MkdirAll(filepath.Dir(r.filename), os.ModePerm); err != nil {
+		return
+	}
+
+	f, err := r.fs.OpenAppend(r.filename, filePerm)
 	if err != nil {
 		return
 	}
-	info, err := file.Stat()
+	info, err := f.Stat()
 	if err != nil {
 		return
 	}
-	r.nbytes = int(info.Size())
-	r.w = NewWriteCloser(file)
+	r.nbytes = info.Size()
+	r.f = f
+	if r.wrap != nil {
+		// Not buffer the data before the wrapper, so the data flushed by
+		// the wrapper, such as GzipHandler, is counted in time.
+		r.w = newUnbufferedWriteCloser(r.wrap(&countWriteCloser{WriteCloser: f, n: &r.nbytes}))
+	} else {
+		r.w = NewWriteCloser(f)
+	}
+
+	size := r.nbytes
+	if err = r.writeBanner(); err != nil {
+		r.w.Close()
+		r.w = nil
+		return
+	}
+
+	if r.onOpen != nil {
+		var n int
+		if n, err = r.onOpen(r.w, size); err != nil {
+			r.w.Close()
+			r.w = nil
+			return
+		}
+		r.addBytes(n)
+	}
+	r.empty = size == 0
 	return
 }
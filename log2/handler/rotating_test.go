@@ -1,7 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-tools/file"
 )
 
 func ExampleTimedRotatingFile() {
@@ -33,3 +43,324 @@ func ExampleSizedRotatingFile() {
 	// Output:
 	// Success
 }
+
+func TestTimedRotatingFileBackups(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewTimedRotatingFile(filename, 2)
+	defer h.Close()
+
+	expected := []string{
+		filename + ".2017-12-30",
+		filename + ".2017-12-31",
+		filename + ".2018-01-01",
+	}
+	for _, fn := range []string{expected[2], expected[0], expected[1], filename + ".bak"} {
+		if err := ioutil.WriteFile(fn, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if backups, err := h.Backups(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(backups, expected) {
+		t.Errorf("expected %v, but got %v", expected, backups)
+	}
+}
+
+func TestSizedRotatingFileBackups(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 10, 3)
+	defer h.Close()
+
+	for i := 0; i < 12; i++ {
+		h.WriteString("0123456789")
+	}
+
+	expected := []string{filename + ".1", filename + ".2", filename + ".3"}
+	if backups, err := h.Backups(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(backups, expected) {
+		t.Errorf("expected %v, but got %v", expected, backups)
+	}
+}
+
+func TestSizedRotatingFileRotateMarker(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 1024, 5)
+	h.SetRotateMarker([]byte("--ROTATE--\n"))
+
+	h.WriteString("a\n")
+	if n, err := h.WriteString("b\n--ROTATE--\nc\n"); err != nil || n != 15 {
+		t.Errorf("unexpected the result of the write: %d, %v", n, err)
+	}
+	h.WriteString("--ROTATE--")
+	h.WriteString("d--ROTATE--\n")
+	h.Close()
+
+	expected := map[string]string{
+		filename + ".2": "a\nb\n",
+		filename + ".1": "c\n",
+		filename:        "d--ROTATE--\n",
+	}
+	for fn, content := range expected {
+		if data, err := ioutil.ReadFile(fn); err != nil {
+			t.Error(err)
+		} else if string(data) != content {
+			t.Errorf("%s: expected %q, but got %q", fn, content, data)
+		}
+	}
+}
+
+func TestSizedRotatingFileRotateMarkerOversized(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 16, 5)
+	h.SetAtomicRecords(true)
+	h.SetRotateMarker([]byte("--ROTATE--\n"))
+
+	big := strings.Repeat("x", 32) + "\n"
+	h.WriteString(big) // The file exceeds the max size.
+	h.WriteString("--ROTATE--\n")
+	h.Close()
+
+	// The marker rotates the file only once, without the empty backup.
+	if data, _ := ioutil.ReadFile(filename + ".1"); string(data) != big {
+		t.Errorf("expected %q, but got %q", big, data)
+	}
+	if backups, _ := h.Backups(); len(backups) != 1 {
+		t.Errorf("expected 1 backup, but got %v", backups)
+	}
+}
+
+func TestRotatingFileCreateDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs", "app")
+	NewTimedRotatingFile(filepath.Join(dir, "timed.log"), 1).Close()
+	NewSizedRotatingFile(filepath.Join(dir, "sized.log"), 1024, 1).Close()
+	for _, fn := range []string{"timed.log", "sized.log"} {
+		if _, err := ioutil.ReadFile(filepath.Join(dir, fn)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestTimedRotatingFileAbsFailed(t *testing.T) {
+	defer func(abs func(string) (string, error)) { filepathAbs = abs }(filepathAbs)
+	filepathAbs = func(string) (string, error) { return "", errors.New("abs failed") }
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewTimedRotatingFile(filename, 1)
+	defer h.Close()
+
+	if h.filename != filename {
+		t.Errorf("expected the filename '%s', but got '%s'", filename, h.filename)
+	}
+}
+
+func TestTimedRotatingFileDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	now := time.Date(2021, 3, 14, 0, 30, 0, 0, loc) // Spring forward at 02:00.
+	timeNow = func() time.Time { return now }
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewTimedRotatingFile(filename, 10)
+	defer h.Close()
+
+	for _, expected := range []time.Time{
+		time.Date(2021, 3, 15, 0, 0, 0, 0, loc),
+		time.Date(2021, 3, 16, 0, 0, 0, 0, loc),
+	} {
+		if rotatorAt := time.Unix(h.rotatorAt, 0).In(loc); !rotatorAt.Equal(expected) {
+			t.Fatalf("expected the rollover at %s, but got %s", expected, rotatorAt)
+		}
+
+		now = expected.Add(time.Minute)
+		h.WriteString("data\n")
+	}
+
+	now = time.Date(2021, 11, 7, 12, 0, 0, 0, loc) // Fall back at 02:00.
+	h.WriteString("data\n")
+	if rotatorAt, expected := time.Unix(h.rotatorAt, 0).In(loc),
+		time.Date(2021, 11, 8, 0, 0, 0, 0, loc); !rotatorAt.Equal(expected) {
+		t.Errorf("expected the rollover at %s, but got %s", expected, rotatorAt)
+	}
+
+	backups, _ := h.Backups()
+	expected := []string{filename + ".2021-03-14", filename + ".2021-03-15", filename + ".2021-03-16"}
+	if !reflect.DeepEqual(backups, expected) {
+		t.Errorf("expected the backups %v, but got %v", expected, backups)
+	}
+}
+
+func TestResetDefaultFilePerm(t *testing.T) {
+	defer ResetDefaultFilePerm(int(FilePerm))
+	ResetDefaultFilePerm(0600)
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 1024, 1)
+	h.Close()
+
+	if info, err := os.Stat(filename); err != nil {
+		t.Fatal(err)
+	} else if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected the permission 0600, but got %s", perm)
+	}
+}
+
+func TestStartupBanner(t *testing.T) {
+	banner := func() []byte { return []byte("# pid=1 host=localhost\n") }
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 64, 2)
+	h.SetStartupBanner(banner)
+	for i := 0; i < 4; i++ {
+		h.WriteString("0123456789012345678901234567890\n")
+	}
+	h.Close()
+
+	for _, fn := range []string{filename + ".1", filename} {
+		data, _ := ioutil.ReadFile(fn)
+		if n := strings.Count(string(data), "# pid=1"); n != 1 || !strings.HasPrefix(string(data), "# pid=1") {
+			t.Errorf("%s: expected the banner once at the top, but got %q", fn, data)
+		}
+		if len(data) > 64 {
+			t.Errorf("%s: expected the banner to be counted in the size, but got %d", fn, len(data))
+		}
+	}
+
+	filename = filepath.Join(filepath.Dir(filename), "timed.log")
+	th := NewTimedRotatingFile(filename, 2)
+	th.SetStartupBanner(banner)
+	th.WriteString("line 1\n")
+	th.SetStartupBanner(banner)
+	th.WriteString("line 2\n")
+	th.Close()
+
+	if data, _ := ioutil.ReadFile(filename); string(data) != "# pid=1 host=localhost\nline 1\nline 2\n" {
+		t.Errorf("unexpected the content: %q", data)
+	}
+}
+
+func TestTimedRotatingFileActiveDated(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewTimedRotatingFile(filename, 2)
+	defer h.Close()
+
+	h.WriteString("undated\n")
+	h.rotatorAt -= day // Pretend that the current file was opened yesterday.
+	h.periodAt = h.periodAt.AddDate(0, 0, -1)
+	yesterday := h.datedFilename()
+	if err := h.SetActiveDated(true); err != nil {
+		t.Fatal(err)
+	}
+	if link, err := os.Readlink(filename); err != nil || link != filepath.Base(yesterday) {
+		t.Fatalf("expected the link to '%s', but got '%s', %v", yesterday, link, err)
+	}
+
+	h.WriteString("rotated\n") // Rotate to today.
+	today := h.datedFilename()
+	if link, err := os.Readlink(filename); err != nil || link != filepath.Base(today) {
+		t.Fatalf("expected the link to '%s', but got '%s', %v", today, link, err)
+	}
+
+	if data, _ := ioutil.ReadFile(yesterday); string(data) != "undated\n" {
+		t.Errorf("unexpected the content of the backup: %q", data)
+	}
+	if data, _ := ioutil.ReadFile(filename); string(data) != "rotated\n" {
+		t.Errorf("unexpected the content of the active file: %q", data)
+	}
+	if backups, _ := h.Backups(); !reflect.DeepEqual(backups, []string{yesterday}) {
+		t.Errorf("unexpected the backups: %v", backups)
+	}
+
+	if err := h.SetActiveDated(false); err != nil {
+		t.Fatal(err)
+	}
+	h.WriteString("undated again\n")
+	if isSymlink(filename) || file.IsExist(today) {
+		t.Error("expected the dated file to be renamed back")
+	}
+	if data, _ := ioutil.ReadFile(filename); string(data) != "rotated\nundated again\n" {
+		t.Errorf("unexpected the content of the active file: %q", data)
+	}
+}
+
+func TestSizedRotatingFileSetFilename(t *testing.T) {
+	dir := t.TempDir()
+	oldfile := filepath.Join(dir, "old.log")
+	newfile := filepath.Join(dir, "new", "new.log")
+
+	h := NewSizedRotatingFile(oldfile, 1024, 1)
+	h.WriteString("before\n")
+	if err := h.SetFilename(newfile); err != nil {
+		t.Fatal(err)
+	}
+	h.WriteString("after\n")
+	h.Close()
+
+	if data, _ := ioutil.ReadFile(oldfile); string(data) != "before\n" {
+		t.Errorf("unexpected the content of the old file: %q", data)
+	}
+	if data, _ := ioutil.ReadFile(newfile); string(data) != "after\n" {
+		t.Errorf("unexpected the content of the new file: %q", data)
+	}
+}
+
+func TestSizedRotatingFileSetFilenameError(t *testing.T) {
+	dir := t.TempDir()
+	oldfile := filepath.Join(dir, "old.log")
+	// The parent of the new file is a regular file, so it cannot be opened.
+	newfile := filepath.Join(oldfile, "new.log")
+
+	h := NewSizedRotatingFile(oldfile, 1024, 1)
+	h.WriteString("before\n")
+	if err := h.SetFilename(newfile); err == nil {
+		t.Fatal("expected the error to open the new file")
+	}
+	if _, err := h.WriteString("after\n"); err != nil {
+		t.Fatal(err)
+	}
+	h.Close()
+
+	if data, _ := ioutil.ReadFile(oldfile); string(data) != "before\nafter\n" {
+		t.Errorf("unexpected the content of the old file: %q", data)
+	}
+}
+
+func TestSizedRotatingFileLargeSize(t *testing.T) {
+	// The sparse file whose size overflows int32 and uint32.
+	const size = 5 << 30
+	filename := filepath.Join(t.TempDir(), "test.log")
+	if f, err := os.Create(filename); err != nil {
+		t.Fatal(err)
+	} else if err = f.Truncate(size); err != nil {
+		f.Close()
+		t.Skipf("cannot create the sparse file: %v", err)
+	} else {
+		f.Close()
+	}
+
+	h := NewSizedRotatingFile64(filename, size+10, 1)
+	defer h.Close()
+
+	if h.nbytes != size {
+		t.Fatalf("expected the size %d, but got %d", int64(size), h.nbytes)
+	}
+
+	h.WriteString("12345\n") // Not rotated.
+	if backups, _ := h.Backups(); len(backups) != 0 {
+		t.Errorf("unexpected the backups: %v", backups)
+	}
+
+	h.WriteString("12345\n") // Rotated, since it exceeds the max size.
+	if backups, _ := h.Backups(); len(backups) != 1 {
+		t.Errorf("expected 1 backup, but got %v", backups)
+	} else if fsize, _ := file.Size(backups[0]); fsize != size+6 {
+		t.Errorf("expected the backup size %d, but got %d", int64(size+6), fsize)
+	}
+	if h.nbytes != 6 {
+		t.Errorf("expected the size 6 after rotating, but got %d", h.nbytes)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// lineSequencer prepends the incrementing sequence number to each line,
+// which may be split across the writes.
+type lineSequencer struct {
+	seq uint64
+	mid bool // Whether the last write ends in the middle of a line.
+}
+
+// prefix returns the data whose lines are prefixed by the sequence numbers,
+// such as "1 line\n".
+func (s *lineSequencer) prefix(data []byte) []byte {
+	buf := make([]byte, 0, len(data)+16)
+	for len(data) > 0 {
+		if !s.mid {
+			s.seq++
+			buf = strconv.AppendUint(buf, s.seq, 10)
+			buf = append(buf, ' ')
+		}
+
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			buf = append(buf, data...)
+			s.mid = true
+			break
+		}
+
+		buf = append(buf, data[:i+1]...)
+		data = data[i+1:]
+		s.mid = false
+	}
+	return buf
+}
+
+// SetSequencePrefix enables or disables the sequence prefix, that's,
+// each line written is prefixed by the monotonically increasing sequence
+// number starting from 1 and a space, like "1 line\n", which is used to
+// detect the gaps or the loss in the log delivery.
+//
+// The sequence number is kept across the rollover, but starts from 1 again
+// when the process restarts or the sequence prefix is enabled again.
+func (t *TimedRotatingFile) SetSequencePrefix(enable bool) {
+	t.Lock()
+	if !enable {
+		t.seq = nil
+	} else if t.seq == nil {
+		t.seq = new(lineSequencer)
+	}
+	t.Unlock()
+}
+
+// SetSequencePrefix is the same as TimedRotatingFile.SetSequencePrefix.
+//
+// The prefixes are counted in the size of the file, but the marker lines
+// set by SetRotateMarker are not prefixed.
+func (r *SizedRotatingFile) SetSequencePrefix(enable bool) {
+	r.Lock()
+	if !enable {
+		r.seq = nil
+	} else if r.seq == nil {
+		r.seq = new(lineSequencer)
+	}
+	r.Unlock()
+}
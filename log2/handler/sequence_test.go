@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSizedRotatingFileSequencePrefix(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewSizedRotatingFile(filename, 32, 10)
+	h.SetSequencePrefix(true)
+	for i := 0; i < 10; i++ {
+		if n, err := fmt.Fprintf(h, "line%d\n", i); err != nil {
+			t.Fatal(err)
+		} else if n != 6 {
+			t.Errorf("expected 6 bytes written, but got %d", n)
+		}
+	}
+	h.Close()
+
+	backups, _ := h.Backups()
+	if len(backups) == 0 {
+		t.Fatal("expected the rollover")
+	}
+
+	var lines []string
+	for i := len(backups) - 1; i >= -1; i-- {
+		fn := filename
+		if i >= 0 {
+			fn = backups[i]
+		}
+		data, _ := ioutil.ReadFile(fn)
+		lines = append(lines, strings.SplitAfter(string(data), "\n")...)
+	}
+
+	var i int
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		if expected := fmt.Sprintf("%d line%d\n", i+1, i); line != expected {
+			t.Fatalf("expected %q, but got %q", expected, line)
+		}
+		i++
+	}
+	if i != 10 {
+		t.Errorf("expected 10 lines, but got %d", i)
+	}
+}
+
+func TestTimedRotatingFileSequencePrefix(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewTimedRotatingFile(filename, 10)
+	h.SetSequencePrefix(true)
+	h.WriteString("a")
+	h.WriteString("b\nc\n")
+	h.rotatorAt = 0 // Force the rollover.
+	h.WriteString("d\ne")
+	h.SetSequencePrefix(false)
+	h.WriteString("\nf\n")
+	h.Close()
+
+	backups, _ := h.Backups()
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup, but got %v", backups)
+	}
+	if data, _ := ioutil.ReadFile(backups[0]); string(data) != "1 ab\n2 c\n" {
+		t.Errorf("unexpected the backup: %q", data)
+	}
+	if data, _ := ioutil.ReadFile(filename); string(data) != "3 d\n4 e\nf\n" {
+		t.Errorf("unexpected the active file: %q", data)
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// ShardedRotatingFile is a logging handler writing the lines round-robin
+// across N SizedRotatingFiles named "filename.0" to "filename.N-1",
+// which is convenient for the log shipper reading the shards in parallel.
+//
+// Each shard rotates independently.
+type ShardedRotatingFile struct {
+	sync.Mutex
+	shards []*SizedRotatingFile
+	next   int
+}
+
+// NewShardedRotatingFile returns a new ShardedRotatingFile with n shards.
+//
+// size and count are the same as NewSizedRotatingFile for each shard.
+// If failed, it will panic.
+func NewShardedRotatingFile(filename string, n, size, count int) *ShardedRotatingFile {
+	if n < 1 {
+		panic(fmt.Errorf("the number of the shards must be positive"))
+	}
+
+	shards := make([]*SizedRotatingFile, n)
+	for i := range shards {
+		shards[i] = NewSizedRotatingFile(fmt.Sprintf("%s.%d", filename, i), size, count)
+	}
+	return &ShardedRotatingFile{shards: shards}
+}
+
+// Write implements the interface io.Writer.
+//
+// The data is split into the lines, and each line, including the last one
+// without the newline, is written into the next shard as a whole.
+func (s *ShardedRotatingFile) Write(data []byte) (n int, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i > -1 {
+			line = data[:i+1]
+		}
+
+		m, err := s.shards[s.next].Write(line)
+		n += m
+		if err != nil {
+			return n, err
+		}
+
+		s.next = (s.next + 1) % len(s.shards)
+		data = data[len(line):]
+	}
+	return
+}
+
+// WriteString writes the string.
+func (s *ShardedRotatingFile) WriteString(data string) (n int, err error) {
+	return s.Write([]byte(data))
+}
+
+// Close implements the interface io.Closer, which closes all the shards.
+func (s *ShardedRotatingFile) Close() (err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, shard := range s.shards {
+		if _err := shard.Close(); _err != nil && err == nil {
+			err = _err
+		}
+	}
+	return
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type shardedEntry struct {
+	key     string
+	w       io.WriteCloser // nil if being opened by the factory
+	refs    int
+	closing bool
+}
+
+// ShardedHandler routes each record to the handler of its key, such as
+// the per-tenant log file, which is created lazily by the factory.
+//
+// The number of the opened handlers is bounded. When exceeding it,
+// the least-recently-used idle handler is evicted and closed, so it's able
+// to maintain lots of the per-key logs without exhausting the file
+// descriptors. If all the handlers are being written, the write waits
+// until one of them finishes. And the handler of a key is not reopened
+// until the evicted one has been closed.
+//
+// It's safe to be written concurrently, and the records of the different
+// keys are written in parallel. So the handler returned by the factory
+// must be safe for the concurrent writes, such as SizedRotatingFile.
+type ShardedHandler struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	closed  bool
+	max     int
+	keyf    func(record []byte) string
+	factory func(key string) io.WriteCloser
+	lru     *list.List // the idle or being written handlers, not closing
+	entries map[string]*list.Element
+}
+
+// NewShardedHandler returns a new ShardedHandler, which opens max handlers
+// at most.
+//
+// keyf extracts the key from each record, and factory creates the handler
+// for the key.
+func NewShardedHandler(max int, keyf func(record []byte) string,
+	factory func(key string) io.WriteCloser) *ShardedHandler {
+
+	if max < 1 {
+		panic(fmt.Errorf("the max number of the handlers must be positive"))
+	}
+
+	h := &ShardedHandler{
+		max:     max,
+		keyf:    keyf,
+		factory: factory,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element, max),
+	}
+	h.cond = sync.NewCond(&h.lock)
+	return h
+}
+
+// Write implements the interface io.Writer, which writes the data as
+// a record into the handler of its key.
+func (h *ShardedHandler) Write(data []byte) (n int, err error) {
+	e, err := h.acquire(h.keyf(data))
+	if err != nil {
+		return
+	}
+	n, err = e.w.Write(data)
+	h.release(e)
+	return
+}
+
+// WriteString writes the string data as a record.
+func (h *ShardedHandler) WriteString(data string) (n int, err error) {
+	return h.Write([]byte(data))
+}
+
+// Len returns the number of the opened handlers.
+func (h *ShardedHandler) Len() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return len(h.entries)
+}
+
+// Close closes all the opened handlers, which waits for the writing
+// handlers to finish.
+func (h *ShardedHandler) Close() (err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.closed = true
+	h.cond.Broadcast()
+	for len(h.entries) > 0 {
+		if ok, _err := h.closeIdle(); !ok {
+			h.cond.Wait()
+		} else if _err != nil && err == nil {
+			err = _err
+		}
+	}
+	return
+}
+
+func (h *ShardedHandler) acquire(key string) (*shardedEntry, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for {
+		if h.closed {
+			return nil, ErrFileNotOpen
+		}
+
+		if elem, ok := h.entries[key]; ok {
+			e := elem.Value.(*shardedEntry)
+			if e.w == nil || e.closing {
+				h.cond.Wait()
+				continue
+			}
+
+			h.lru.MoveToFront(elem)
+			e.refs++
+			return e, nil
+		}
+
+		if len(h.entries) < h.max {
+			break
+		}
+
+		if ok, _ := h.closeIdle(); !ok {
+			h.cond.Wait()
+		}
+	}
+
+	// Reserve the slot, and open the handler without the lock.
+	e := &shardedEntry{key: key, refs: 1}
+	h.entries[key] = h.lru.PushFront(e)
+	h.lock.Unlock()
+	w := h.factory(key)
+	h.lock.Lock()
+
+	e.w = w
+	h.cond.Broadcast()
+	return e, nil
+}
+
+func (h *ShardedHandler) release(e *shardedEntry) {
+	h.lock.Lock()
+	if e.refs--; e.refs == 0 {
+		h.cond.Broadcast()
+	}
+	h.lock.Unlock()
+}
+
+// closeIdle closes the least-recently-used handler not being written,
+// which must be called with the lock held, and releases the lock
+// during closing the handler.
+//
+// It returns false if all the handlers are being written or closed.
+func (h *ShardedHandler) closeIdle() (ok bool, err error) {
+	for elem := h.lru.Back(); elem != nil; elem = elem.Prev() {
+		if e := elem.Value.(*shardedEntry); e.refs == 0 {
+			h.lru.Remove(elem)
+			e.closing = true
+
+			h.lock.Unlock()
+			err = e.w.Close()
+			h.lock.Lock()
+
+			delete(h.entries, e.key)
+			h.cond.Broadcast()
+			return true, err
+		}
+	}
+	return false, nil
+}
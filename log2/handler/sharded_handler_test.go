@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testKeyWriter struct {
+	sync.Mutex
+	bytes.Buffer
+	closed bool
+}
+
+func (w *testKeyWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	if w.closed {
+		return 0, ErrFileNotOpen
+	}
+	return w.Buffer.Write(p)
+}
+
+func (w *testKeyWriter) Close() error {
+	w.Lock()
+	w.closed = true
+	w.Unlock()
+	return nil
+}
+
+func TestShardedHandler(t *testing.T) {
+	var lock sync.Mutex
+	writers := make(map[string][]*testKeyWriter)
+	h := NewShardedHandler(2, func(record []byte) string {
+		return string(record[:strings.IndexByte(string(record), ':')])
+	}, func(key string) io.WriteCloser {
+		lock.Lock()
+		defer lock.Unlock()
+		w := &testKeyWriter{}
+		writers[key] = append(writers[key], w)
+		return w
+	})
+
+	h.WriteString("a:1\n")
+	h.WriteString("b:1\n")
+	h.WriteString("a:2\n")
+	h.WriteString("c:1\n") // Evict b, which is the least-recently-used.
+
+	if n := h.Len(); n != 2 {
+		t.Errorf("expected 2 opened handlers, but got %d", n)
+	}
+	if w := writers["b"][0]; !w.closed || w.String() != "b:1\n" {
+		t.Errorf("unexpected the evicted handler: closed=%v, data=%q", w.closed, w.String())
+	}
+	if w := writers["a"][0]; w.closed || w.String() != "a:1\na:2\n" {
+		t.Errorf("unexpected the handler: closed=%v, data=%q", w.closed, w.String())
+	}
+
+	h.WriteString("b:2\n") // Reopen b, and evict a.
+	if n := len(writers["b"]); n != 2 || !writers["a"][0].closed {
+		t.Errorf("expected b to be reopened and a to be evicted, but got %d", n)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := h.WriteString(fmt.Sprintf("k%d:%d\n", (i+j)%5, j)); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	h.Close()
+	if _, err := h.WriteString("a:3\n"); err != ErrFileNotOpen {
+		t.Errorf("expected ErrFileNotOpen, but got %v", err)
+	}
+
+	var total int
+	for _, ws := range writers {
+		for _, w := range ws {
+			if !w.closed {
+				t.Error("expected all the handlers to be closed")
+			}
+			total += strings.Count(w.String(), "\n")
+		}
+	}
+	if total != 805 {
+		t.Errorf("expected 805 records, but got %d", total)
+	}
+}
+
+type testSlowKeyWriter struct {
+	testKeyWriter
+	key    string
+	opened map[string]int
+	lock   *sync.Mutex
+}
+
+func (w *testSlowKeyWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	return w.testKeyWriter.Write(p)
+}
+
+func (w *testSlowKeyWriter) Close() error {
+	time.Sleep(time.Millisecond)
+	w.lock.Lock()
+	w.opened[w.key]--
+	w.opened[""]--
+	w.lock.Unlock()
+	return w.testKeyWriter.Close()
+}
+
+func TestShardedHandlerBounded(t *testing.T) {
+	var lock sync.Mutex
+	var maxOpened int
+	opened := make(map[string]int)
+	h := NewShardedHandler(2, func(record []byte) string {
+		return string(record[:strings.IndexByte(string(record), ':')])
+	}, func(key string) io.WriteCloser {
+		time.Sleep(time.Millisecond)
+		lock.Lock()
+		defer lock.Unlock()
+		if opened[key]++; opened[key] > 1 {
+			t.Errorf("the handler of the key '%s' is opened twice", key)
+		}
+		if opened[""]++; opened[""] > maxOpened {
+			maxOpened = opened[""]
+		}
+		return &testSlowKeyWriter{key: key, opened: opened, lock: &lock}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := h.WriteString(fmt.Sprintf("k%d:%d\n", (i+j)%4, j)); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	h.Close()
+
+	if maxOpened > 2 {
+		t.Errorf("expected 2 opened handlers at most, but got %d", maxOpened)
+	}
+	if opened[""] != 0 {
+		t.Errorf("expected all the handlers to be closed, but got %d", opened[""])
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestShardedRotatingFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewShardedRotatingFile(filename, 4, 512, 100)
+	for i := 0; i < 200; i++ {
+		h.WriteString(fmt.Sprintf("line %03d\nline %03d\n", 2*i, 2*i+1))
+	}
+	h.Close()
+
+	for i := 0; i < 4; i++ {
+		shard := fmt.Sprintf("%s.%d", filename, i)
+		var lines int
+		for _, fn := range []string{shard + ".1", shard} {
+			data, err := ioutil.ReadFile(fn)
+			if err != nil {
+				t.Fatalf("the shard %d has not rotated: %s", i, err)
+			}
+			lines += bytes.Count(data, []byte("\n"))
+		}
+		if lines != 100 {
+			t.Errorf("the shard %d expected 100 lines, but got %d", i, lines)
+		}
+	}
+}
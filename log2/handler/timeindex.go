@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const indexSuffix = ".idx"
+
+// TimeIndexEntry is an entry of the time index, which indicates that
+// the records written since Time start at Offset in the log file.
+type TimeIndexEntry struct {
+	Time   time.Time
+	Offset int64
+}
+
+// SetTimeIndex enables the sidecar time index of the log file if interval
+// is positive, or disables it.
+//
+// The index is the file with the suffix ".idx" next to the log file,
+// such as "app.log.idx", which is renamed together with the log file on
+// rollover. An entry mapping the current time to the byte offset of the log
+// file is appended before the write at least interval after the last one,
+// and before the first write of each new file.
+//
+// The index is append-only, one entry per line, so a crash only tears
+// the last line at most, which is not terminated by the newline. The torn
+// line is ignored by ReadTimeIndex, and truncated when opening the index
+// again, so the next entry is not glued onto it. So it's able to jump near
+// a time by SearchTimeIndex without scanning the whole log file.
+func (t *TimedRotatingFile) SetTimeIndex(interval time.Duration) (err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if interval <= 0 {
+		t.indexInterval = 0
+		return t.closeIndex()
+	}
+
+	t.indexInterval = interval
+	if t.w != nil && t.index == nil {
+		err = t.openIndex()
+	}
+	return
+}
+
+func (t *TimedRotatingFile) openIndex() (err error) {
+	if t.indexInterval <= 0 {
+		return
+	}
+
+	active := t.activeFilename()
+	fi, err := t.fs.Stat(active)
+	if err != nil {
+		return
+	}
+	t.offset = fi.Size()
+	t.indexedAt = time.Time{}
+	if err = truncateTornLine(t.fs, active+indexSuffix); err != nil {
+		return
+	}
+	t.index, err = t.fs.OpenAppend(active+indexSuffix, filePerm)
+	return
+}
+
+// truncateTornLine truncates the file back to the end of its last line
+// terminated by the newline, that's, removes the torn line by the crash.
+func truncateTornLine(fs FileSystem, filename string) error {
+	fi, err := fs.Stat(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	f, err := fs.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 4096)
+	end := fi.Size()
+	for end > 0 {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+
+		n, err := f.ReadAt(buf[:end-start], start)
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			end = start + int64(i) + 1
+			break
+		}
+		end = start
+	}
+
+	if end == fi.Size() {
+		return nil
+	}
+	return fs.Truncate(filename, end)
+}
+
+func (t *TimedRotatingFile) closeIndex() (err error) {
+	if t.index != nil {
+		err = t.index.Close()
+		t.index = nil
+	}
+	return
+}
+
+func (t *TimedRotatingFile) writeIndex() (err error) {
+	now := timeNow()
+	if now.Sub(t.indexedAt) < t.indexInterval {
+		return
+	}
+
+	if _, err = fmt.Fprintf(t.index, "%d %d\n", now.UnixNano(), t.offset); err == nil {
+		t.indexedAt = now
+	}
+	return
+}
+
+// renameWithIndex renames the log file and its time index if exists.
+func (t *TimedRotatingFile) renameWithIndex(oldpath, newpath string) (err error) {
+	if err = t.fs.Rename(oldpath, newpath); err == nil && t.isFile(oldpath+indexSuffix) {
+		err = t.fs.Rename(oldpath+indexSuffix, newpath+indexSuffix)
+	}
+	return
+}
+
+// ReadTimeIndex reads the entries from the time index file, such as
+// "app.log.idx". The torn lines, that's, not terminated by the newline,
+// and the invalid lines are ignored.
+func ReadTimeIndex(indexFile string) (entries []TimeIndexEntry, err error) {
+	f, err := os.Open(indexFile)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			return entries, nil // The torn line without the newline, if any.
+		} else if err != nil {
+			return entries, err
+		}
+
+		if entry, ok := parseTimeIndexEntry(line[:len(line)-1]); ok {
+			entries = append(entries, entry)
+		}
+	}
+}
+
+// parseTimeIndexEntry parses the line "<nsec> <offset>" of the time index.
+func parseTimeIndexEntry(line string) (entry TimeIndexEntry, ok bool) {
+	fields := strings.Split(line, " ")
+	if len(fields) != 2 {
+		return
+	}
+
+	nsec, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return
+	}
+	offset, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || offset < 0 {
+		return
+	}
+	return TimeIndexEntry{Time: time.Unix(0, nsec), Offset: offset}, true
+}
+
+// SearchTimeIndex returns the offset of the log file to start reading
+// the records written at or after the time t, that's, the offset of
+// the last entry not after t, or 0 if no such entry.
+//
+// The entries must be in the chronological order, such as by ReadTimeIndex.
+func SearchTimeIndex(entries []TimeIndexEntry, t time.Time) int64 {
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].Time.After(t) })
+	if i == 0 {
+		return 0
+	}
+	return entries[i-1].Offset
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimedRotatingFileTimeIndex(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewTimedRotatingFile(filename, 2)
+	defer h.Close()
+
+	h.WriteString("before the index\n")
+	if err := h.SetTimeIndex(time.Nanosecond); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := []string{"line 1\n", "line 22\n", "line 333\n"}
+	var times []time.Time
+	for _, line := range lines {
+		time.Sleep(time.Millisecond)
+		h.WriteString(line)
+		times = append(times, time.Now())
+	}
+
+	entries, err := ReadTimeIndex(filename + indexSuffix)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, but got %v", entries)
+	}
+
+	data, _ := ioutil.ReadFile(filename)
+	for i, line := range lines {
+		offset := SearchTimeIndex(entries, times[i])
+		if offset != entries[i].Offset || string(data[offset:offset+int64(len(line))]) != line {
+			t.Errorf("%d: unexpected the offset %d", i, offset)
+		}
+	}
+	if offset := SearchTimeIndex(entries, times[0].Add(-time.Hour)); offset != 0 {
+		t.Errorf("expected the offset 0 before the first entry, but got %d", offset)
+	}
+
+	// Append a torn entry, then rotate the file.
+	f, _ := os.OpenFile(filename+indexSuffix, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("123")
+	f.Close()
+	h.rotatorAt -= day
+	h.periodAt = h.periodAt.AddDate(0, 0, -1)
+	backup := h.datedFilename()
+	h.WriteString("rotated\n")
+
+	if backups, _ := h.Backups(); !reflect.DeepEqual(backups, []string{backup}) {
+		t.Errorf("unexpected the backups: %v", backups)
+	}
+	if _entries, err := ReadTimeIndex(backup + indexSuffix); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(_entries, entries) {
+		t.Errorf("expected the rotated index %v, but got %v", entries, _entries)
+	}
+	if entries, _ = ReadTimeIndex(filename + indexSuffix); len(entries) != 1 || entries[0].Offset != 0 {
+		t.Errorf("unexpected the index of the new file: %v", entries)
+	}
+}
+
+func TestTimeIndexTornTail(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	h := NewTimedRotatingFile(filename, 2)
+	h.SetTimeIndex(time.Nanosecond)
+	h.WriteString("line 1\n")
+	time.Sleep(time.Millisecond)
+	h.WriteString("line 2\n")
+	h.Close()
+
+	entries, err := ReadTimeIndex(filename + indexSuffix)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected 2 entries, but got %v, %v", entries, err)
+	}
+
+	// Tear the entry "<nsec> 4567\n" as the crash does.
+	f, _ := os.OpenFile(filename+indexSuffix, os.O_APPEND|os.O_WRONLY, 0644)
+	fmt.Fprintf(f, "%d 45", time.Now().UnixNano())
+	f.Close()
+	if _entries, _ := ReadTimeIndex(filename + indexSuffix); !reflect.DeepEqual(_entries, entries) {
+		t.Errorf("expected the torn entry to be ignored, but got %v", _entries)
+	}
+
+	// Reopen the handler, and the torn entry is truncated.
+	h = NewTimedRotatingFile(filename, 2)
+	defer h.Close()
+	h.SetTimeIndex(time.Nanosecond)
+	h.WriteString("line 3\n")
+
+	entries, err = ReadTimeIndex(filename + indexSuffix)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected 3 entries, but got %v, %v", entries, err)
+	}
+	if entries[2].Offset != 14 {
+		t.Errorf("expected the offset 14 of the new entry, but got %d", entries[2].Offset)
+	}
+	if data, _ := ioutil.ReadFile(filename + indexSuffix); bytes.Count(data, []byte("\n")) != 3 ||
+		data[len(data)-1] != '\n' {
+		t.Errorf("unexpected the index file %q", data)
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// TriggerHandler buffers the records in memory, and only flushes them to
+// the target handler when a record at or above the trigger level arrives,
+// like logging.handlers.MemoryHandler in Python.
+//
+// So it's able to keep the debug context in memory and only write it when
+// an error actually occurs. The buffer is bounded, and the oldest record is
+// dropped when it's full. The buffered records are also flushed on Close.
+type TriggerHandler struct {
+	sync.Mutex
+	w       io.WriteCloser
+	trigger Level
+	levelf  func(data []byte) Level
+
+	records [][]byte
+	start   int
+	count   int
+}
+
+// NewTriggerHandler returns a new TriggerHandler, which buffers capacity
+// records at most and flushes them to w when a record at or above trigger
+// arrives.
+func NewTriggerHandler(w io.WriteCloser, trigger Level, capacity int) *TriggerHandler {
+	if capacity < 1 {
+		panic(fmt.Errorf("the capacity must be positive"))
+	}
+
+	return &TriggerHandler{
+		w:       w,
+		trigger: trigger,
+		levelf:  func([]byte) Level { return LevelDebug },
+		records: make([][]byte, capacity),
+	}
+}
+
+// SetLevelFunc sets the function to extract the level of the record
+// written by Write. By default, it's LevelDebug, so Write never triggers
+// the flush.
+func (h *TriggerHandler) SetLevelFunc(f func(data []byte) Level) {
+	h.Lock()
+	h.levelf = f
+	h.Unlock()
+}
+
+// Write implements the interface io.Writer, which writes the data as
+// the record with the level extracted by the level function.
+func (h *TriggerHandler) Write(data []byte) (n int, err error) {
+	h.Lock()
+	defer h.Unlock()
+	return h.writeLevel(h.levelf(data), data)
+}
+
+// WriteString writes the string as the record by Write.
+func (h *TriggerHandler) WriteString(data string) (n int, err error) {
+	return h.Write([]byte(data))
+}
+
+// WriteLevel writes the data as the record with the level.
+func (h *TriggerHandler) WriteLevel(level Level, data []byte) (n int, err error) {
+	h.Lock()
+	defer h.Unlock()
+	return h.writeLevel(level, data)
+}
+
+func (h *TriggerHandler) writeLevel(level Level, data []byte) (n int, err error) {
+	if level >= h.trigger {
+		if err = h.flush(); err != nil {
+			return
+		}
+		return h.w.Write(data)
+	}
+
+	record := append([]byte(nil), data...)
+	if h.count == len(h.records) {
+		// Drop the oldest record.
+		h.records[h.start] = record
+		h.start = (h.start + 1) % len(h.records)
+	} else {
+		h.records[(h.start+h.count)%len(h.records)] = record
+		h.count++
+	}
+	return len(data), nil
+}
+
+// Flush writes all the buffered records to the target handler.
+func (h *TriggerHandler) Flush() (err error) {
+	h.Lock()
+	err = h.flush()
+	h.Unlock()
+	return
+}
+
+func (h *TriggerHandler) flush() (err error) {
+	for ; h.count > 0; h.count-- {
+		record := h.records[h.start]
+		if _, err = h.w.Write(record); err != nil {
+			return
+		}
+		h.records[h.start] = nil
+		h.start = (h.start + 1) % len(h.records)
+	}
+	h.start = 0
+	return
+}
+
+// Close flushes the buffered records and closes the target handler.
+func (h *TriggerHandler) Close() (err error) {
+	h.Lock()
+	defer h.Unlock()
+
+	err = h.flush()
+	if _err := h.w.Close(); err == nil {
+		err = _err
+	}
+	return
+}
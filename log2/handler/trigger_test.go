@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTriggerHandler(t *testing.T) {
+	w := &testKeyWriter{}
+	h := NewTriggerHandler(w, LevelError, 3)
+	h.SetLevelFunc(func(data []byte) Level {
+		if bytes.HasPrefix(data, []byte("ERROR")) {
+			return LevelError
+		}
+		return LevelDebug
+	})
+
+	h.WriteString("debug 1\n")
+	h.WriteLevel(LevelInfo, []byte("info 2\n"))
+	if w.Len() != 0 {
+		t.Fatalf("expected the records to be buffered, but got %q", w.String())
+	}
+
+	h.WriteString("ERROR 3\n")
+	if expected := "debug 1\ninfo 2\nERROR 3\n"; w.String() != expected {
+		t.Fatalf("expected %q, but got %q", expected, w.String())
+	}
+
+	w.Reset()
+	for _, record := range []string{"a\n", "b\n", "c\n", "d\n", "e\n"} {
+		h.WriteString(record)
+	}
+	h.WriteLevel(LevelFatal, []byte("fatal\n"))
+	if expected := "c\nd\ne\nfatal\n"; w.String() != expected {
+		t.Fatalf("expected the oldest to be dropped %q, but got %q", expected, w.String())
+	}
+
+	w.Reset()
+	h.WriteString("f\n")
+	h.Close()
+	if w.String() != "f\n" || !w.closed {
+		t.Errorf("expected to flush on Close, but got %q", w.String())
+	}
+}
@@ -9,7 +9,7 @@ import (
 // WriteCloser implements the interface io.WriteCloser with the buffer.
 type WriteCloser struct {
 	w   io.WriteCloser
-	buf *bufio.Writer
+	buf *bufio.Writer // nil if unbuffered
 }
 
 // NewWriteCloser returns a new WriteCloser.
@@ -20,6 +20,13 @@ func NewWriteCloser(w io.WriteCloser) *WriteCloser {
 	}
 }
 
+// newUnbufferedWriteCloser returns a new WriteCloser without the buffer,
+// which writes the data into w directly, such as the wrapper of the file
+// buffering the data by itself.
+func newUnbufferedWriteCloser(w io.WriteCloser) *WriteCloser {
+	return &WriteCloser{w: w}
+}
+
 // Closed returns true if having been closed, or false.
 func (wc *WriteCloser) Closed() bool {
 	return wc.w == nil
@@ -27,18 +34,51 @@ func (wc *WriteCloser) Closed() bool {
 
 // Write implements the interface io.Writer.
 func (wc *WriteCloser) Write(data []byte) (int, error) {
+	if wc.buf == nil {
+		return wc.w.Write(data)
+	}
 	return wc.buf.Write(data)
 }
 
+// Flush flushes the buffered data into the underlying writer, then flushes
+// the underlying writer if it has the method Flush() error, such as
+// GzipHandler.
+func (wc *WriteCloser) Flush() (err error) {
+	if wc.buf != nil {
+		if err = wc.buf.Flush(); err != nil {
+			return
+		}
+	}
+
+	if f, ok := wc.w.(interface{ Flush() error }); ok {
+		err = f.Flush()
+	}
+	return
+}
+
 // Close implements the interface io.Closer.
 func (wc *WriteCloser) Close() (err error) {
-	wc.buf.Flush()
+	if wc.buf != nil {
+		wc.buf.Flush()
+		wc.buf.Reset(os.Stderr)
+	}
 	err = wc.w.Close()
 	wc.w = nil
-	wc.buf.Reset(os.Stderr)
 	return err
 }
 
+// countWriteCloser counts the bytes written into the underlying writer.
+type countWriteCloser struct {
+	io.WriteCloser
+	n *int64
+}
+
+func (c *countWriteCloser) Write(data []byte) (n int, err error) {
+	n, err = c.WriteCloser.Write(data)
+	*c.n += int64(n)
+	return
+}
+
 // NullWriter is a null writer, which implements the interface io.WriteCloser.
 // When writing the data, it will discard the data and return.
 type NullWriter struct{}